@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"MyBlog/internal/anchor"
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// anchorPlaceholderRegex 匹配 README 模板中 anchor 函数生成的占位符
+	anchorPlaceholderRegex = regexp.MustCompile("\x00anchor:(\\d+)\x00")
+	// atxHeadingRegex 匹配 "## 标题" 形式的标题
+	atxHeadingRegex = regexp.MustCompile(`^ {0,3}#{1,6}(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	// fenceRegex 匹配代码块的开始和结束
+	fenceRegex = regexp.MustCompile("^ {0,3}(```|~~~)")
+	// inlineLinkRegex 匹配标题中的链接和图片，只保留链接文字
+	inlineLinkRegex = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	// htmlTagRegex 匹配标题中的 HTML 标签
+	htmlTagRegex = regexp.MustCompile(`<[^>]+>`)
+)
+
+// anchorLinks 记录 README 模板中通过 anchor 函数引用的标题
+//
+// 锚点与标题在整个 README 中的位置有关（重复的标题会添加 -1、-2 后缀），
+// 所以模板中先输出占位符，README 生成后再按文档中实际的标题替换。
+type anchorLinks struct {
+	headings []string
+}
+
+// placeholder 记录引用的标题并返回占位符
+func (l *anchorLinks) placeholder(heading string) string {
+	l.headings = append(l.headings, heading)
+	return fmt.Sprintf("\x00anchor:%d\x00", len(l.headings)-1)
+}
+
+// resolve 将占位符替换为标题的锚点
+//
+// 第 n 次引用某个标题对应文档中第 n 个同名的标题，文档中没有该标题时按规则直接生成。
+func (l *anchorLinks) resolve(content []byte, profile anchor.Profile) []byte {
+	if len(l.headings) == 0 {
+		return content
+	}
+
+	anchors := documentAnchors(content, profile)
+	resolved := make([]string, len(l.headings))
+	used := make(map[string]int)
+	for i, heading := range l.headings {
+		text := headingText(heading)
+		if candidates := anchors[text]; used[text] < len(candidates) {
+			resolved[i] = candidates[used[text]]
+		} else {
+			resolved[i] = anchor.Make(text, profile)
+		}
+		used[text]++
+	}
+
+	return anchorPlaceholderRegex.ReplaceAllFunc(content, func(match []byte) []byte {
+		i, _ := strconv.Atoi(string(anchorPlaceholderRegex.FindSubmatch(match)[1]))
+		return []byte(resolved[i])
+	})
+}
+
+// documentAnchors 按顺序为文档中的标题生成锚点，返回标题文字对应的锚点列表
+func documentAnchors(content []byte, profile anchor.Profile) map[string][]string {
+	generator := anchor.NewGenerator(profile)
+	anchors := make(map[string][]string)
+
+	fence := ""
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := fenceRegex.FindStringSubmatch(line); m != nil {
+			switch fence {
+			case "":
+				fence = m[1]
+			case m[1]:
+				fence = ""
+			}
+			continue
+		}
+		if fence != "" {
+			continue
+		}
+
+		m := atxHeadingRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		text := headingText(m[1])
+		anchors[text] = append(anchors[text], generator.Anchor(text))
+	}
+	return anchors
+}
+
+// headingText 标题渲染后的文字，去掉链接、HTML 标签和行内格式
+func headingText(heading string) string {
+	text := inlineLinkRegex.ReplaceAllString(heading, "$1")
+	text = htmlTagRegex.ReplaceAllString(text, "")
+	text = strings.NewReplacer("`", "", "**", "", "~~", "").Replace(text)
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"MyBlog/internal/article"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var AssetCmd = &cobra.Command{
+	Use:   "asset",
+	Short: "管理文章的图片等资源文件",
+	Long: `管理文章的图片等资源文件。
+
+每篇文章的资源放在与文章同名的 .assets 目录中，例如 Go/并发/channel.md 的
+资源目录为 Go/并发/channel.assets/。发布 (pub)、撤回 (unpub) 和移动 (mv)
+文章时，资源目录会随文章一起移动。`,
+	Example: `  myblog asset add Go/并发/channel.md ~/Pictures/goroutine.png`,
+}
+
+var assetAddCmd = &cobra.Command{
+	Use:   "add <article> <file>",
+	Short: "将文件复制到文章的资源目录并输出Markdown引用",
+	Long: `将图片等文件复制到文章的资源目录中，并输出可以直接粘贴到文章中的Markdown。
+
+文章路径可以相对于草稿目录或博客目录，先在草稿目录中查找。
+资源目录中已有同名但内容不同的文件时，会自动在文件名后添加编号。`,
+	Args: cobra.ExactArgs(2),
+	Run:  runAssetAddCommand,
+}
+
+func init() {
+	AssetCmd.AddCommand(assetAddCmd)
+}
+
+func runAssetAddCommand(cmd *cobra.Command, args []string) {
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+
+	articlePath, err := articleRepository().FindAny(args[0])
+	if err != nil {
+		fmt.Printf("%s %v\n", red("错误:"), err)
+		return
+	}
+
+	assetPath, err := addAsset(articlePath, args[1])
+	if err != nil {
+		fmt.Printf("%s 添加资源失败: %v\n", red("错误:"), err)
+		logrus.WithError(err).Error("添加资源失败")
+		return
+	}
+
+	fmt.Printf("%s 成功添加资源!\n", green("✓"))
+	fmt.Printf("  文件路径: %s\n", green(assetPath))
+	fmt.Println("  Markdown:")
+	fmt.Printf("\n%s\n", assetSnippet(articlePath, assetPath))
+
+	logrus.WithFields(logrus.Fields{
+		"article": articlePath,
+		"asset":   assetPath,
+	}).Info("资源添加成功")
+}
+
+// addAsset 将文件复制到文章的资源目录，返回复制后的路径
+func addAsset(articlePath, sourcePath string) (string, error) {
+	repo := articleRepository()
+	info, err := repo.Stat(sourcePath)
+	if err != nil {
+		return "", fmt.Errorf("读取资源文件失败: %v", err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("不支持添加目录: %s", sourcePath)
+	}
+
+	content, err := repo.ReadFile(sourcePath)
+	if err != nil {
+		return "", fmt.Errorf("读取资源文件失败: %v", err)
+	}
+
+	dir := article.AssetsDir(articlePath)
+	name := filepath.Base(sourcePath)
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+
+	// 同名文件内容相同时直接复用，不同时添加编号
+	targetPath := filepath.Join(dir, name)
+	for i := 1; ; i++ {
+		existing, err := repo.ReadFile(targetPath)
+		if os.IsNotExist(err) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("读取已有资源失败: %v", err)
+		}
+		if bytes.Equal(existing, content) {
+			return targetPath, nil
+		}
+		targetPath = filepath.Join(dir, base+"-"+strconv.Itoa(i)+ext)
+	}
+
+	if err := repo.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("创建资源目录失败: %v", err)
+	}
+	if err := repo.WriteFile(targetPath, content, 0644); err != nil {
+		return "", fmt.Errorf("写入资源文件失败: %v", err)
+	}
+	return targetPath, nil
+}
+
+// assetSnippet 生成引用资源的Markdown，图片使用图片语法
+func assetSnippet(articlePath, assetPath string) string {
+	relPath, err := filepath.Rel(filepath.Dir(articlePath), assetPath)
+	if err != nil {
+		relPath = assetPath
+	}
+	link := article.EscapeDestination(filepath.ToSlash(relPath))
+
+	name := filepath.Base(assetPath)
+	alt := strings.TrimSuffix(name, filepath.Ext(name))
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".png", ".jpg", ".jpeg", ".gif", ".svg", ".webp", ".bmp", ".avif":
+		return fmt.Sprintf("![%s](%s)", alt, link)
+	default:
+		return fmt.Sprintf("[%s](%s)", name, link)
+	}
+}
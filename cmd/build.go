@@ -0,0 +1,603 @@
+package cmd
+
+import (
+	"MyBlog/internal/article"
+	"MyBlog/internal/config"
+	"MyBlog/internal/frontmatter"
+	"MyBlog/internal/fsys"
+	"MyBlog/internal/slug"
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+var (
+	buildOutDir  string
+	buildClean   bool
+	buildVerbose bool
+)
+
+var BuildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "将博客目录渲染为完整的静态HTML站点",
+	Long: `将博客目录中已发布的文章渲染为完整的静态HTML站点。
+
+该命令会：
+1. 扫描blogs目录中的所有文章（与gen命令相同）
+2. 将每篇文章的Markdown正文渲染为HTML页面
+3. 生成首页、每个标签路径的分类页和每篇文章的页面
+4. 复制文章目录中的图片等静态文件
+
+生成的站点使用相对链接，可以部署到任意目录或直接在本地打开。
+
+页面地址：
+  首页:     index.html
+  标签页:   tags/<标签路径>/index.html
+  文章页:   posts/<文章相对路径>/index.html`,
+	Example: `  myblog build
+  myblog build --out public/
+  myblog build --out public/ --clean`,
+	Args: cobra.NoArgs,
+	Run:  runBuildCommand,
+}
+
+func init() {
+	BuildCmd.Flags().StringVarP(&buildOutDir, "out", "o", "public", "站点输出目录，不能位于博客目录或草稿目录中")
+	BuildCmd.Flags().BoolVar(&buildClean, "clean", false, "构建前清空输出目录")
+	BuildCmd.Flags().BoolVarP(&buildVerbose, "verbose", "v", false, "详细输出")
+}
+
+func runBuildCommand(cmd *cobra.Command, args []string) {
+	// 设置颜色输出
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	blue := color.New(color.FgBlue).SprintFunc()
+
+	if buildVerbose {
+		logrus.SetLevel(logrus.DebugLevel)
+	}
+
+	if err := checkOutputDir(buildOutDir); err != nil {
+		fmt.Printf("%s %v\n", red("错误:"), err)
+		logrus.WithError(err).Error("输出目录无效")
+		return
+	}
+
+	fmt.Printf("%s 开始扫描已发布的文章...\n", blue("信息:"))
+
+	articles, err := scanPublishedArticles()
+	if err != nil {
+		fmt.Printf("%s 扫描文章失败: %v\n", red("错误:"), err)
+		logrus.WithError(err).Error("扫描文章失败")
+		return
+	}
+
+	if len(articles) == 0 {
+		fmt.Printf("%s 没有找到已发布的文章\n", yellow("提示:"))
+		return
+	}
+
+	if buildClean {
+		if err := cleanOutputDir(buildOutDir); err != nil {
+			fmt.Printf("%s 清空输出目录失败: %v\n", red("错误:"), err)
+			return
+		}
+	}
+
+	builder := newSiteBuilder(buildOutDir)
+	pages, err := builder.buildAll(articles)
+	if err != nil {
+		fmt.Printf("%s 构建站点失败: %v\n", red("错误:"), err)
+		logrus.WithError(err).Error("构建站点失败")
+		return
+	}
+
+	// 设置了站点地址时一并生成订阅源和站点地图
+	feedFiles := 0
+	if config.GetBaseURL() != "" {
+		files, err := writeFeeds(articles, buildOutDir)
+		if err != nil {
+			fmt.Printf("%s 生成订阅源失败: %v\n", red("错误:"), err)
+			logrus.WithError(err).Error("生成订阅源失败")
+			return
+		}
+		feedFiles = len(files)
+
+		if _, err := writeSitemap(articles, buildOutDir); err != nil {
+			fmt.Printf("%s 生成站点地图失败: %v\n", red("错误:"), err)
+			logrus.WithError(err).Error("生成站点地图失败")
+			return
+		}
+	}
+
+	fmt.Printf("%s 成功构建静态站点!\n", green("✓"))
+	fmt.Printf("  文章总数: %s\n", yellow(fmt.Sprintf("%d", len(articles))))
+	fmt.Printf("  生成页面: %s\n", yellow(fmt.Sprintf("%d", pages)))
+	if feedFiles > 0 {
+		fmt.Printf("  订阅源: %s\n", yellow(fmt.Sprintf("%d", feedFiles)))
+		fmt.Printf("  站点地图: %s\n", green("sitemap.xml, robots.txt"))
+	} else {
+		fmt.Printf("  %s 设置 site.base_url 后会同时生成订阅源和站点地图\n", yellow("提示:"))
+	}
+	fmt.Printf("  输出目录: %s\n", green(buildOutDir))
+
+	logrus.WithFields(logrus.Fields{
+		"articles_count": len(articles),
+		"pages":          pages,
+		"out":            buildOutDir,
+	}).Info("静态站点构建成功")
+}
+
+// checkOutputDir 拒绝位于博客目录或草稿目录中的输出目录，生成的页面不能混入文章中
+func checkOutputDir(outDir string) error {
+	absOut, err := filepath.Abs(outDir)
+	if err != nil {
+		return err
+	}
+	for _, articleDir := range []string{config.GetBlogsDir(), config.GetDraftDir()} {
+		absArticleDir, err := filepath.Abs(articleDir)
+		if err != nil {
+			return err
+		}
+		if absOut == absArticleDir || strings.HasPrefix(absOut, absArticleDir+string(filepath.Separator)) {
+			return fmt.Errorf("输出目录 %s 位于文章目录 %s 中，请使用文章目录之外的目录", outDir, articleDir)
+		}
+	}
+	return nil
+}
+
+// cleanOutputDir 清空输出目录，拒绝清空当前目录或文章所在目录
+func cleanOutputDir(outDir string) error {
+	absOut, err := filepath.Abs(outDir)
+	if err != nil {
+		return err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	for _, protected := range []string{cwd, config.GetBlogsDir(), config.GetDraftDir()} {
+		absProtected, err := filepath.Abs(protected)
+		if err != nil {
+			return err
+		}
+		if absOut == absProtected || strings.HasPrefix(absProtected, absOut+string(filepath.Separator)) {
+			return fmt.Errorf("输出目录 %s 包含项目文件，拒绝清空", outDir)
+		}
+	}
+
+	return fsys.RemoveAll(outDir)
+}
+
+// siteBuilder 负责将文章渲染为HTML页面
+type siteBuilder struct {
+	outDir        string
+	includeDrafts bool // 同时渲染草稿目录中的文章（serve --drafts 使用）
+	liveReload    bool // 在页面中注入实时刷新脚本（serve命令使用）
+	markdown      goldmark.Markdown
+}
+
+// sitePage 渲染页面模板使用的数据
+type sitePage struct {
+	SiteTitle     string
+	Title         string
+	Root          string // 从当前页面到站点根目录的相对路径
+	Article       *siteArticle
+	Content       template.HTML
+	Group         *siteGroup
+	Groups        []siteGroup
+	TotalArticles int
+	LiveReload    bool
+	GeneratedAt   time.Time
+}
+
+type siteArticle struct {
+	GenArticleInfo
+	URL string // 相对于站点根目录的地址
+}
+
+type siteGroup struct {
+	TagPath  string
+	URL      string
+	Articles []siteArticle
+}
+
+func newSiteBuilder(outDir string) *siteBuilder {
+	return &siteBuilder{
+		outDir: outDir,
+		markdown: goldmark.New(
+			goldmark.WithExtensions(extension.GFM),
+			goldmark.WithRendererOptions(html.WithUnsafe()),
+		),
+	}
+}
+
+// buildAll 渲染全部页面并复制静态文件，返回生成的页面数量
+func (b *siteBuilder) buildAll(articles []GenArticleInfo) (int, error) {
+	pages := 0
+	for _, article := range articles {
+		if !b.renders(article) {
+			continue
+		}
+		if err := b.buildArticle(article); err != nil {
+			return pages, err
+		}
+		pages++
+	}
+
+	listPages, err := b.buildIndexes(articles)
+	if err != nil {
+		return pages, err
+	}
+	pages += listPages
+
+	if err := b.copyStaticFiles(config.GetBlogsDir(), "posts"); err != nil {
+		return pages, err
+	}
+	if b.includeDrafts {
+		if err := b.copyStaticFiles(config.GetDraftDir(), "drafts"); err != nil {
+			return pages, err
+		}
+	}
+
+	return pages, nil
+}
+
+// buildIndexes 渲染首页和所有标签分类页，返回生成的页面数量
+func (b *siteBuilder) buildIndexes(articles []GenArticleInfo) (int, error) {
+	var listed []GenArticleInfo
+	for _, article := range articles {
+		if b.renders(article) && !article.Unlisted {
+			listed = append(listed, article)
+		}
+	}
+
+	tagGroups := groupArticlesByTags(listed)
+	groups := make([]siteGroup, len(tagGroups))
+	for i, group := range tagGroups {
+		groups[i] = siteGroup{
+			TagPath:  group.TagPath,
+			URL:      escapeURLPath(tagPagePath(group.TagPath)),
+			Articles: make([]siteArticle, len(group.Articles)),
+		}
+		for j, article := range group.Articles {
+			groups[i].Articles[j] = newSiteArticle(article)
+		}
+	}
+
+	index := b.newPage("", config.GetSiteTitle())
+	index.Groups = groups
+	index.TotalArticles = len(listed)
+	if err := b.writePage("", "index", index); err != nil {
+		return 0, err
+	}
+
+	for i := range groups {
+		page := b.newPage(tagPagePath(groups[i].TagPath), groups[i].TagPath)
+		page.Group = &groups[i]
+		if err := b.writePage(tagPagePath(groups[i].TagPath), "tag", page); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(groups) + 1, nil
+}
+
+// renders 判断文章是否需要渲染：Front Matter 标记为草稿的文章只在预览草稿时渲染
+func (b *siteBuilder) renders(article GenArticleInfo) bool {
+	return b.includeDrafts || (!article.FromDrafts && !article.Draft)
+}
+
+// buildArticle 渲染单篇文章页面
+func (b *siteBuilder) buildArticle(article GenArticleInfo) error {
+	doc, err := frontmatter.ParseFile(article.FilePath)
+	if err != nil {
+		return fmt.Errorf("解析文章失败 %s: %v", article.FilePath, err)
+	}
+
+	var content bytes.Buffer
+	if err := b.renderMarkdown(&content, []byte(doc.Body), article.FilePath, article.Title); err != nil {
+		return fmt.Errorf("渲染文章失败 %s: %v", article.FilePath, err)
+	}
+
+	siteArticle := newSiteArticle(article)
+	pagePath := articlePagePath(article)
+	page := b.newPage(pagePath, article.Title)
+	page.Article = &siteArticle
+	page.Content = template.HTML(content.String())
+
+	logrus.WithField("path", pagePath).Debug("渲染文章页面")
+	return b.writePage(pagePath, "article", page)
+}
+
+// renderMarkdown 将Markdown正文渲染为HTML
+//
+// 正文开头与标题 title 相同的一级标题会被移除，页面模板已经显示了标题；指向其他文件的相对链接统一加上 "../"，
+// 因为文章页面比源文件多一层目录（a.md → a/index.html）。
+func (b *siteBuilder) renderMarkdown(w io.Writer, source []byte, articlePath, title string) error {
+	doc := b.markdown.Parser().Parse(text.NewReader(source))
+
+	if first := doc.FirstChild(); first != nil {
+		if heading, ok := first.(*ast.Heading); ok && heading.Level == 1 &&
+			headingText(nodeSource(heading, source)) == strings.TrimSpace(title) {
+			doc.RemoveChild(doc, first)
+		}
+	}
+
+	ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := node.(type) {
+		case *ast.Link:
+			n.Destination = rewriteRelativeLink(n.Destination, articlePath)
+		case *ast.Image:
+			n.Destination = rewriteRelativeLink(n.Destination, articlePath)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	return b.markdown.Renderer().Render(w, source, doc)
+}
+
+// nodeSource 块级节点在 Markdown 中的原始内容，例如标题去掉 # 之后的文字
+func nodeSource(node ast.Node, source []byte) string {
+	var buf bytes.Buffer
+	lines := node.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		buf.Write(segment.Value(source))
+	}
+	return buf.String()
+}
+
+// rewriteRelativeLink 将文章中的相对链接转换为站点内的链接
+//
+// 指向其他文章的链接使用目标文章的 slug，articlePath 用于定位目标文章。
+func rewriteRelativeLink(destination []byte, articlePath string) []byte {
+	link := string(destination)
+	if !article.IsRelativeLink(link) {
+		return destination
+	}
+
+	target, fragment := link, ""
+	if i := strings.IndexAny(link, "?#"); i >= 0 {
+		target, fragment = link[:i], link[i:]
+	}
+	if strings.HasSuffix(strings.ToLower(target), ".md") {
+		fmSlug := ""
+		if filePath, err := url.PathUnescape(target); err == nil {
+			filePath = filepath.Join(filepath.Dir(articlePath), filepath.FromSlash(filePath))
+			if doc, err := frontmatter.ParseFile(filePath); err == nil {
+				fmSlug = doc.Metadata().Slug
+			}
+		}
+
+		name := articleSlug(path.Base(target), fmSlug) + "/"
+		if dir := path.Dir(target); dir != "." {
+			name = dir + "/" + name
+		}
+		target = name
+	}
+
+	return []byte("../" + target + fragment)
+}
+
+// copyStaticFiles 将文章目录中的非Markdown文件复制到对应的文章页面目录旁
+func (b *siteBuilder) copyStaticFiles(srcDir, prefix string) error {
+	if _, err := os.Stat(srcDir); os.IsNotExist(err) {
+		return nil
+	}
+
+	return filepath.Walk(srcDir, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || strings.HasSuffix(strings.ToLower(info.Name()), ".md") || strings.HasPrefix(info.Name(), ".") {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(srcDir, filePath)
+		if err != nil {
+			return err
+		}
+		targetPath := filepath.Join(b.outDir, prefix, relPath)
+
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			return fmt.Errorf("读取静态文件失败: %v", err)
+		}
+		if err := fsys.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+			return fmt.Errorf("创建目录失败: %v", err)
+		}
+		return fsys.WriteFile(targetPath, content, 0644)
+	})
+}
+
+func (b *siteBuilder) newPage(pagePath, title string) *sitePage {
+	return &sitePage{
+		SiteTitle:   config.GetSiteTitle(),
+		Title:       title,
+		Root:        relativeRoot(pagePath),
+		LiveReload:  b.liveReload,
+		GeneratedAt: time.Now(),
+	}
+}
+
+// writePage 使用指定模板渲染页面并写入 <pagePath>/index.html
+func (b *siteBuilder) writePage(pagePath, templateName string, page *sitePage) error {
+	var buf bytes.Buffer
+	if err := siteTemplates.ExecuteTemplate(&buf, templateName, page); err != nil {
+		return fmt.Errorf("渲染页面失败 %s: %v", pagePath, err)
+	}
+
+	targetDir := filepath.Join(b.outDir, filepath.FromSlash(pagePath))
+	if err := fsys.MkdirAll(targetDir, 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %v", err)
+	}
+
+	return fsys.WriteFile(filepath.Join(targetDir, "index.html"), buf.Bytes(), 0644)
+}
+
+func newSiteArticle(article GenArticleInfo) siteArticle {
+	return siteArticle{
+		GenArticleInfo: article,
+		URL:            escapeURLPath(articlePagePath(article)),
+	}
+}
+
+// articlePagePath 文章页面的目录路径，例如 posts/Go/基础/hello/，草稿位于 drafts/ 下
+func articlePagePath(article GenArticleInfo) string {
+	prefix := "posts/"
+	if article.FromDrafts {
+		prefix = "drafts/"
+	}
+	name := articleSlug(path.Base(article.RelativePath), article.Slug) + "/"
+	if dir := path.Dir(article.RelativePath); dir != "." {
+		name = dir + "/" + name
+	}
+	return prefix + name
+}
+
+// articleSlug 文章页面地址的最后一段
+//
+// 优先使用 Front Matter 中的 slug，否则按 slug.strategy 配置由文件名生成，
+// keep 策略直接使用文件名。
+func articleSlug(fileName, fmSlug string) string {
+	if fmSlug != "" {
+		return slug.Make(fmSlug, slug.Keep)
+	}
+
+	stem := strings.TrimSuffix(fileName, path.Ext(fileName))
+	strategy, err := slug.ParseStrategy(config.GetSlugStrategy())
+	if err != nil || strategy == slug.Keep {
+		return stem
+	}
+	return slug.Make(stem, strategy)
+}
+
+// tagPagePath 标签分类页的目录路径，例如 tags/Go/基础/
+func tagPagePath(tagPath string) string {
+	return "tags/" + tagPath + "/"
+}
+
+// relativeRoot 计算从页面目录返回站点根目录的相对路径
+func relativeRoot(pagePath string) string {
+	depth := strings.Count(strings.Trim(pagePath, "/"), "/")
+	if strings.Trim(pagePath, "/") == "" {
+		return "./"
+	}
+	return strings.Repeat("../", depth+1)
+}
+
+// escapeURLPath 对路径的每一段进行URL转义
+func escapeURLPath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+var siteTemplates = template.Must(template.New("site").Funcs(template.FuncMap{
+	"date": func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format("2006-01-02")
+	},
+	"joinTags": func(tags []string) string {
+		return strings.Join(tags, "/")
+	},
+	"tagURL": func(tags []string) template.URL {
+		return template.URL(escapeURLPath(tagPagePath(strings.Join(tags, "/"))))
+	},
+	"safeURL": func(s string) template.URL {
+		return template.URL(s)
+	},
+}).Parse(`
+{{define "header"}}<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{if ne .Title .SiteTitle}}{{.Title}} - {{end}}{{.SiteTitle}}</title>
+<style>
+body { max-width: 800px; margin: 0 auto; padding: 1rem 1.5rem; font-family: -apple-system, "PingFang SC", "Microsoft YaHei", sans-serif; line-height: 1.7; color: #24292f; }
+header.site { border-bottom: 1px solid #d0d7de; margin-bottom: 1.5rem; }
+header.site a { color: inherit; text-decoration: none; font-size: 1.4rem; font-weight: 600; }
+a { color: #0969da; }
+.meta { color: #57606a; font-size: 0.9rem; }
+pre { background: #f6f8fa; padding: 1rem; overflow: auto; border-radius: 6px; }
+code { font-family: SFMono-Regular, Consolas, monospace; }
+img { max-width: 100%; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #d0d7de; padding: 0.3rem 0.8rem; }
+footer { margin-top: 3rem; color: #57606a; font-size: 0.85rem; border-top: 1px solid #d0d7de; }
+</style>
+</head>
+<body>
+<header class="site"><p><a href="{{safeURL .Root}}">{{.SiteTitle}}</a></p></header>
+<main>
+{{end}}
+
+{{define "footer"}}</main>
+<footer><p>使用 MyBlog 生成于 {{.GeneratedAt.Format "2006-01-02 15:04"}}</p></footer>
+{{if .LiveReload}}<script>
+(function () {
+  var source = new EventSource("/__livereload");
+  source.onmessage = function () { location.reload(); };
+})();
+</script>
+{{end}}</body>
+</html>
+{{end}}
+
+{{define "index"}}{{template "header" .}}
+<h1>📚 文章导航</h1>
+<p class="meta">共 {{len .Groups}} 个标签分类，{{.TotalArticles}} 篇文章</p>
+<ul>
+{{range .Groups}}<li><a href="{{safeURL $.Root}}{{safeURL .URL}}">{{.TagPath}}</a> ({{len .Articles}}篇)</li>
+{{end}}</ul>
+{{range .Groups}}
+<h2><a href="{{safeURL $.Root}}{{safeURL .URL}}">{{.TagPath}}</a></h2>
+<ul>
+{{range .Articles}}<li><a href="{{safeURL $.Root}}{{safeURL .URL}}">{{.Title}}</a> <span class="meta">{{date .Published}}</span></li>
+{{end}}</ul>
+{{end}}
+{{template "footer" .}}{{end}}
+
+{{define "tag"}}{{template "header" .}}
+<h1>{{.Group.TagPath}}</h1>
+<p class="meta">{{len .Group.Articles}} 篇文章</p>
+<ul>
+{{range .Group.Articles}}<li><a href="{{safeURL $.Root}}{{safeURL .URL}}">{{.Title}}</a> <span class="meta">{{date .Published}}</span></li>
+{{end}}</ul>
+{{template "footer" .}}{{end}}
+
+{{define "article"}}{{template "header" .}}
+<article>
+<h1>{{.Article.Title}}</h1>
+<p class="meta">{{if .Article.FromDrafts}}<strong>草稿</strong> · {{end}}{{date .Article.Published}}{{if .Article.Tags}} · <a href="{{safeURL .Root}}{{tagURL .Article.Tags}}">{{joinTags .Article.Tags}}</a>{{end}}</p>
+{{.Content}}
+</article>
+{{template "footer" .}}{{end}}
+`))
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestRenderMarkdownTitleHeading 只移除与文章标题相同的一级标题
+func TestRenderMarkdownTitleHeading(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		title   string
+		removed bool
+	}{
+		{"与标题相同", "# Go 并发\n\n正文\n", "Go 并发", true},
+		{"标题前后有空格", "#   Go 并发  \n\n正文\n", " Go 并发 ", true},
+		{"行内格式", "# `Go` **并发**\n\n正文\n", "Go 并发", true},
+		{"Setext 标题", "Go 并发\n===\n\n正文\n", "Go 并发", true},
+		{"与标题不同", "# 第一章\n\n正文\n", "Go 并发", false},
+		{"二级标题", "## Go 并发\n\n正文\n", "Go 并发", false},
+		{"不在开头", "前言\n\n# Go 并发\n", "Go 并发", false},
+	}
+
+	b := newSiteBuilder(t.TempDir())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := b.renderMarkdown(&out, []byte(tt.source), "blogs/go.md", tt.title); err != nil {
+				t.Fatalf("renderMarkdown: %v", err)
+			}
+			html := out.String()
+			if removed := !strings.Contains(html, "<h"); removed != tt.removed {
+				t.Errorf("移除标题 = %v, want %v\n%s", removed, tt.removed, html)
+			}
+			if !strings.Contains(html, "正文") && !strings.Contains(html, "前言") {
+				t.Errorf("正文丢失:\n%s", html)
+			}
+		})
+	}
+}
+
+// TestCheckOutputDir 输出目录不能位于博客目录或草稿目录中
+func TestCheckOutputDir(t *testing.T) {
+	t.Chdir(t.TempDir())
+	for _, outDir := range []string{"blogs/public", "blogs", "./blogs/Go/html", "_draft/site"} {
+		if err := checkOutputDir(outDir); err == nil {
+			t.Errorf("checkOutputDir(%s) 应返回错误", outDir)
+		}
+	}
+	for _, outDir := range []string{"public", "blogs-public", "../site"} {
+		if err := checkOutputDir(outDir); err != nil {
+			t.Errorf("checkOutputDir(%s): %v", outDir, err)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package cmd
+
+import (
+	"MyBlog/internal/article"
+	"MyBlog/internal/frontmatter"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	checkScope  string
+	checkFormat string
+)
+
+var CheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "检查文章的Front Matter、目录结构和内部链接",
+	Long: `检查草稿目录和博客目录中的所有文章，报告以下问题：
+
+错误：
+  - 缺少 Front Matter 或 Front Matter 没有闭合、无法解析
+  - 缺少标题
+  - 日期字段 (date、published、updated、lastmod、publish_at) 无法解析
+  - 相对链接或图片指向不存在的文件
+
+警告：
+  - 标签与文章所在的目录路径不一致
+  - 多篇文章使用相同的标题
+
+存在错误时命令以非零状态退出，可以在 CI 中使用 --format json 获取结果。`,
+	Example: `  myblog check
+  myblog check --scope published
+  myblog check --format json`,
+	Args: cobra.NoArgs,
+	Run:  runCheckCommand,
+}
+
+func init() {
+	CheckCmd.Flags().StringVarP(&checkScope, "scope", "s", "all", "检查范围: drafts, published, all")
+	CheckCmd.Flags().StringVarP(&checkFormat, "format", "f", "text", "输出格式: text, json")
+}
+
+// 问题的级别
+const (
+	checkError   = "error"
+	checkWarning = "warning"
+)
+
+// checkIssue 检查发现的一个问题
+type checkIssue struct {
+	Path    string `json:"path"`
+	Line    int    `json:"line,omitempty"`
+	Level   string `json:"level"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// checkReport 检查结果
+type checkReport struct {
+	Files    int          `json:"files"`
+	Errors   int          `json:"errors"`
+	Warnings int          `json:"warnings"`
+	Issues   []checkIssue `json:"issues"`
+}
+
+// checkedArticle 通过了 Front Matter 检查的文章，用于跨文章的检查
+type checkedArticle struct {
+	path  string
+	title string
+}
+
+func runCheckCommand(cmd *cobra.Command, args []string) {
+	red := color.New(color.FgRed).SprintFunc()
+
+	var statuses []article.Status
+	switch checkScope {
+	case "all":
+		statuses = []article.Status{article.Draft, article.Published}
+	case "drafts":
+		statuses = []article.Status{article.Draft}
+	case "published":
+		statuses = []article.Status{article.Published}
+	default:
+		fmt.Printf("%s 不支持的检查范围: %s (可选: drafts, published, all)\n", red("错误:"), checkScope)
+		return
+	}
+	if checkFormat != "text" && checkFormat != "json" {
+		fmt.Printf("%s 不支持的输出格式: %s (可选: text, json)\n", red("错误:"), checkFormat)
+		return
+	}
+
+	report := checkArticles(statuses)
+
+	var err error
+	if checkFormat == "json" {
+		err = printCheckJSON(report)
+	} else {
+		printCheckText(report)
+	}
+	if err != nil {
+		fmt.Printf("%s %v\n", red("错误:"), err)
+		os.Exit(1)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"files":    report.Files,
+		"errors":   report.Errors,
+		"warnings": report.Warnings,
+	}).Debug("文章检查完成")
+
+	if report.Errors > 0 {
+		os.Exit(1)
+	}
+}
+
+// checkArticles 检查各根目录中的所有文章
+func checkArticles(statuses []article.Status) *checkReport {
+	report := &checkReport{Issues: []checkIssue{}}
+	var articles []checkedArticle
+
+	repo := articleRepository()
+	for _, status := range statuses {
+		root := repo.Root(status)
+		for _, filePath := range repo.Files(status) {
+			report.Files++
+			issues, checked := checkArticle(repo, root, filePath)
+			report.Issues = append(report.Issues, issues...)
+			if checked != nil {
+				articles = append(articles, *checked)
+			}
+		}
+	}
+
+	report.Issues = append(report.Issues, checkDuplicateTitles(articles)...)
+
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		if report.Issues[i].Path != report.Issues[j].Path {
+			return report.Issues[i].Path < report.Issues[j].Path
+		}
+		return report.Issues[i].Line < report.Issues[j].Line
+	})
+	for _, issue := range report.Issues {
+		if issue.Level == checkError {
+			report.Errors++
+		} else {
+			report.Warnings++
+		}
+	}
+	return report
+}
+
+// checkArticle 检查单篇文章，Front Matter 无法解析时返回的文章为 nil
+func checkArticle(repo *article.Repository, root, filePath string) ([]checkIssue, *checkedArticle) {
+	displayPath := filepath.ToSlash(filePath)
+	var issues []checkIssue
+	report := func(line int, level, rule, format string, args ...interface{}) {
+		issues = append(issues, checkIssue{
+			Path:    displayPath,
+			Line:    line,
+			Level:   level,
+			Rule:    rule,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	content, err := repo.ReadFile(filePath)
+	if err != nil {
+		report(0, checkError, "unreadable", "读取文件失败: %v", err)
+		return issues, nil
+	}
+
+	doc, err := frontmatter.Parse(content)
+	switch {
+	case errors.Is(err, frontmatter.ErrMissing):
+		report(1, checkError, "missing-frontmatter", "缺少Front Matter")
+		return issues, nil
+	case errors.Is(err, frontmatter.ErrUnclosed):
+		report(1, checkError, "unclosed-frontmatter", "Front Matter没有闭合")
+		return issues, nil
+	case err != nil:
+		report(1, checkError, "invalid-frontmatter", "解析Front Matter失败: %v", err)
+		return issues, nil
+	}
+
+	title := strings.TrimSpace(doc.String(frontmatter.KeyTitle))
+	if title == "" {
+		report(1, checkError, "missing-title", "缺少标题")
+	}
+
+	for _, key := range []string{frontmatter.KeyDate, frontmatter.KeyPublished, frontmatter.KeyUpdated, frontmatter.KeyLastmod, frontmatter.KeyPublishAt} {
+		if _, err := doc.TimeE(key); err != nil {
+			report(1, checkError, "invalid-date", "%s 字段不是有效的日期: %v", key, doc.Get(key))
+		}
+	}
+
+	// 标签即目录结构
+	if relDir, err := filepath.Rel(root, filepath.Dir(filePath)); err == nil {
+		var dirTags []string
+		if relDir != "." {
+			dirTags = strings.Split(filepath.ToSlash(relDir), "/")
+		}
+		tags := doc.Strings(frontmatter.KeyTags)
+		if strings.Join(tags, "/") != strings.Join(dirTags, "/") {
+			report(1, checkWarning, "tag-mismatch", "标签 %q 与所在目录 %q 不一致", strings.Join(tags, "/"), strings.Join(dirTags, "/"))
+		}
+	}
+
+	bodyOffset := len(content) - len(doc.Body)
+	for _, link := range article.RelativeLinks(doc.Body) {
+		target, err := resolveRelativeLink(filePath, link.Destination)
+		if err != nil {
+			continue
+		}
+		if _, err := repo.Stat(target); err == nil {
+			continue
+		}
+
+		line := lineNumber(content, bodyOffset, link.Destination)
+		if link.Image {
+			report(line, checkError, "broken-image", "图片不存在: %s", link.Destination)
+		} else {
+			report(line, checkError, "broken-link", "链接指向的文件不存在: %s", link.Destination)
+		}
+	}
+
+	if title == "" {
+		return issues, nil
+	}
+	return issues, &checkedArticle{path: displayPath, title: title}
+}
+
+// checkDuplicateTitles 找出标题相同的文章
+func checkDuplicateTitles(articles []checkedArticle) []checkIssue {
+	byTitle := make(map[string][]string)
+	for _, checked := range articles {
+		byTitle[checked.title] = append(byTitle[checked.title], checked.path)
+	}
+
+	var issues []checkIssue
+	for title, paths := range byTitle {
+		if len(paths) < 2 {
+			continue
+		}
+		for _, path := range paths {
+			var others []string
+			for _, other := range paths {
+				if other != path {
+					others = append(others, other)
+				}
+			}
+			issues = append(issues, checkIssue{
+				Path:    path,
+				Line:    1,
+				Level:   checkWarning,
+				Rule:    "duplicate-title",
+				Message: fmt.Sprintf("标题 %q 与其他文章重复: %s", title, strings.Join(others, ", ")),
+			})
+		}
+	}
+	return issues
+}
+
+// resolveRelativeLink 将相对链接解析为文件路径，忽略锚点和查询参数
+func resolveRelativeLink(filePath, link string) (string, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+	if u.Path == "" {
+		return "", fmt.Errorf("链接没有路径: %s", link)
+	}
+	return filepath.Join(filepath.Dir(filePath), filepath.FromSlash(u.Path)), nil
+}
+
+// lineNumber 查找链接在文件中首次出现的行号，找不到时返回正文的第一行
+func lineNumber(content []byte, bodyOffset int, destination string) int {
+	index := strings.Index(string(content[bodyOffset:]), destination)
+	if index < 0 {
+		index = 0
+	}
+	return strings.Count(string(content[:bodyOffset+index]), "\n") + 1
+}
+
+func printCheckText(report *checkReport) {
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	for _, issue := range report.Issues {
+		location := issue.Path
+		if issue.Line > 0 {
+			location = fmt.Sprintf("%s:%d", issue.Path, issue.Line)
+		}
+		mark := yellow("⚠")
+		if issue.Level == checkError {
+			mark = red("✗")
+		}
+		fmt.Printf("%s %s [%s] %s\n", mark, location, issue.Rule, issue.Message)
+	}
+
+	if len(report.Issues) == 0 {
+		fmt.Printf("%s 检查了 %d 篇文章，没有发现问题\n", green("✓"), report.Files)
+		return
+	}
+	fmt.Printf("\n检查了 %d 篇文章，发现 %s 个错误，%s 个警告\n",
+		report.Files, red(report.Errors), yellow(report.Warnings))
+}
+
+func printCheckJSON(report *checkReport) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(report)
+}
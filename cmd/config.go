@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"MyBlog/internal/config"
+	"MyBlog/internal/fsys"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var ConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "查看和修改配置",
+	Long: `查看和修改 MyBlog 的配置。
+
+配置值的来源按优先级从高到低依次为：
+1. 环境变量 (例如 MYBLOG_DIRECTORIES_DRAFT)
+2. 配置文件 config.yaml
+3. 内置默认值`,
+	Example: `  myblog config show
+  myblog config get directories.draft
+  myblog config set directories.draft my_drafts
+  myblog config unset directories.draft`,
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "显示配置文件路径和所有生效的配置项",
+	Args:  cobra.NoArgs,
+	Run:   runConfigShowCommand,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "获取配置项的当前值",
+	Args:  cobra.ExactArgs(1),
+	Run:   runConfigGetCommand,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "设置配置项并写入配置文件",
+	Long: `设置配置项并写入配置文件，文件中已有的注释会被保留。
+
+列表类型的配置项使用逗号分隔多个值。`,
+	Args: cobra.ExactArgs(2),
+	Run:  runConfigSetCommand,
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "从配置文件中删除配置项，恢复为默认值",
+	Args:  cobra.ExactArgs(1),
+	Run:   runConfigUnsetCommand,
+}
+
+func init() {
+	ConfigCmd.AddCommand(configShowCmd)
+	ConfigCmd.AddCommand(configGetCmd)
+	ConfigCmd.AddCommand(configSetCmd)
+	ConfigCmd.AddCommand(configUnsetCmd)
+}
+
+func runConfigShowCommand(cmd *cobra.Command, args []string) {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	blue := color.New(color.FgBlue).SprintFunc()
+
+	fmt.Printf("%s %s\n\n", blue("配置文件:"), green(config.ConfigFile()))
+
+	keys := config.Keys()
+	width := 0
+	for _, key := range keys {
+		if len(key) > width {
+			width = len(key)
+		}
+	}
+
+	for _, key := range keys {
+		source := config.Source(key)
+		fmt.Printf("  %-*s = %s  %s\n", width, key, yellow(formatConfigValue(key)), blue("("+source+")"))
+	}
+}
+
+func runConfigGetCommand(cmd *cobra.Command, args []string) {
+	red := color.New(color.FgRed).SprintFunc()
+
+	key := strings.ToLower(args[0])
+	if !config.IsValidKey(key) {
+		fmt.Printf("%s 未知的配置项: %s\n", red("错误:"), key)
+		printValidConfigKeys()
+		return
+	}
+
+	fmt.Println(formatConfigValue(key))
+}
+
+func runConfigSetCommand(cmd *cobra.Command, args []string) {
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	key := strings.ToLower(args[0])
+	if err := config.Set(key, args[1]); err != nil {
+		fmt.Printf("%s %v\n", red("错误:"), err)
+		if !config.IsValidKey(key) {
+			printValidConfigKeys()
+		}
+		return
+	}
+
+	value := formatConfigValue(key)
+	if fsys.DryRun() {
+		// 预演模式下配置文件没有改变，直接显示将要设置的值
+		value = args[1]
+	}
+	fmt.Printf("%s 已设置 %s = %s\n", green("✓"), key, yellow(value))
+	if config.Source(key) == config.SourceEnv {
+		fmt.Printf("%s 环境变量 %s 已设置，当前生效值仍以环境变量为准\n", yellow("提示:"), config.EnvName(key))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"key":   key,
+		"value": args[1],
+		"file":  config.ConfigFile(),
+	}).Info("配置项已更新")
+}
+
+func runConfigUnsetCommand(cmd *cobra.Command, args []string) {
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	key := strings.ToLower(args[0])
+	if err := config.Unset(key); err != nil {
+		fmt.Printf("%s %v\n", red("错误:"), err)
+		if !config.IsValidKey(key) {
+			printValidConfigKeys()
+		}
+		return
+	}
+
+	fmt.Printf("%s 已删除 %s，当前值: %s (%s)\n", green("✓"), key, yellow(formatConfigValue(key)), config.Source(key))
+
+	logrus.WithFields(logrus.Fields{
+		"key":  key,
+		"file": config.ConfigFile(),
+	}).Info("配置项已删除")
+}
+
+// formatConfigValue 格式化配置项的当前生效值
+func formatConfigValue(key string) string {
+	value := viper.Get(key)
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprint(item)
+		}
+		return strings.Join(items, ",")
+	case []string:
+		return strings.Join(v, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// printValidConfigKeys 列出所有可用的配置项
+func printValidConfigKeys() {
+	fmt.Println("可用配置项:")
+	for _, key := range config.Keys() {
+		fmt.Printf("  - %s\n", key)
+	}
+}
@@ -1,12 +1,10 @@
 package cmd
 
 import (
+	"MyBlog/internal/article"
 	"MyBlog/internal/config"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
-	"time"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/fatih/color"
@@ -17,6 +15,8 @@ import (
 var (
 	draftTags       []string
 	draftTagsString string
+	draftTemplate   string
+	draftSlug       string
 	verbose         bool
 )
 
@@ -37,6 +37,8 @@ var DraftCmd = &cobra.Command{
 func init() {
 	// 添加命令行标志
 	DraftCmd.Flags().StringVarP((*string)(&draftTagsString), "tags", "t", "", "文章标签路径 (使用斜杠分隔创建目录结构，如: Go/基础/教程)")
+	DraftCmd.Flags().StringVar(&draftTemplate, "template", "", "使用模板目录中的文章模板 (<名称>.md.tmpl)，builtin 使用内置格式")
+	DraftCmd.Flags().StringVar(&draftSlug, "slug", "", "文件名和页面地址使用的名称，会写入 Front Matter 的 slug 字段")
 	DraftCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "详细输出")
 
 	// 设置日志级别
@@ -58,24 +60,30 @@ func runDraftCommand(cmd *cobra.Command, args []string) {
 	if len(args) > 0 {
 		title = args[0]
 		// 处理命令行标签参数
-		if draftTagsString != "" {
-			tags := strings.Split(draftTagsString, "/")
-			for _, tag := range tags {
-				tag = strings.TrimSpace(tag)
-				if tag != "" {
-					draftTags = append(draftTags, tag)
-				}
-			}
+		tags, err := article.ParseTags(draftTagsString)
+		if err != nil {
+			fmt.Printf("%s %v\n", red("错误:"), err)
+			return
 		}
+		draftTags = tags
 	} else {
 		// 交互式获取信息
-		articleInfo, err := getArticleInfoInteractively()
+		articleInfo, err := getArticleInfoInteractively(config.GetDraftDir())
 		if err != nil {
 			fmt.Printf("%s %v\n", red("错误:"), err)
 			return
 		}
 		title = articleInfo.Title
 		draftTags = articleInfo.Tags
+
+		// 没有通过 --template 指定时选择模板
+		if draftTemplate == "" {
+			draftTemplate, err = selectTemplateInteractively()
+			if err != nil {
+				fmt.Printf("%s %v\n", red("错误:"), err)
+				return
+			}
+		}
 	}
 
 	if title == "" {
@@ -86,7 +94,12 @@ func runDraftCommand(cmd *cobra.Command, args []string) {
 	fmt.Printf("%s 正在创建草稿: %s\n", blue("信息:"), yellow(title))
 
 	// 创建草稿
-	filePath, err := createDraft(title, draftTags)
+	filePath, err := articleRepository().Create(article.Draft, article.CreateOptions{
+		Title:    title,
+		Tags:     draftTags,
+		Slug:     draftSlug,
+		Template: draftTemplate,
+	})
 	if err != nil {
 		fmt.Printf("%s 创建草稿失败: %v\n", red("错误:"), err)
 		logrus.WithError(err).Error("创建草稿失败")
@@ -113,11 +126,12 @@ type ArticleInfo struct {
 	Tags  []string
 }
 
-func getArticleInfoInteractively() (*ArticleInfo, error) {
+// getArticleInfoInteractively 交互式获取文章标题和标签路径，rootDir 为文章将要创建在的根目录
+func getArticleInfoInteractively(rootDir string) (*ArticleInfo, error) {
 	info := &ArticleInfo{}
 
 	// 获取已有标签路径用于选择
-	existingTagPaths := getExistingTags()
+	existingTagPaths := articleRepository().TagPaths()
 
 	// 1. 获取文章标题
 	titleQuestion := &survey.Input{
@@ -157,7 +171,7 @@ func getArticleInfoInteractively() (*ArticleInfo, error) {
 		var customTagsInput string
 		customTagQuestion := &survey.Input{
 			Message: "请输入标签路径 (使用斜杠分隔创建多级目录):",
-			Help:    fmt.Sprintf("例如: Go/设计模式/单例 → %s/Go/设计模式/单例/", config.GetDraftDir()),
+			Help:    fmt.Sprintf("例如: Go/设计模式/单例 → %s/Go/设计模式/单例/", rootDir),
 		}
 
 		err = survey.AskOne(customTagQuestion, &customTagsInput)
@@ -165,15 +179,9 @@ func getArticleInfoInteractively() (*ArticleInfo, error) {
 			return nil, err
 		}
 
-		if customTagsInput != "" {
-			// 使用斜杠分隔标签路径
-			tags := strings.Split(customTagsInput, "/")
-			for _, tag := range tags {
-				tag = strings.TrimSpace(tag)
-				if tag != "" {
-					finalTags = append(finalTags, tag)
-				}
-			}
+		finalTags, err = article.ParseTags(customTagsInput)
+		if err != nil {
+			return nil, err
 		}
 	} else {
 		// 使用选择的现有标签路径
@@ -181,189 +189,49 @@ func getArticleInfoInteractively() (*ArticleInfo, error) {
 	}
 
 	info.Tags = finalTags
-
 	// 显示最终的目录结构预览
 	if len(finalTags) > 0 {
 		fmt.Printf("\n📁 目录结构预览: %s → %s/%s/\n",
 			strings.Join(finalTags, "/"),
-			config.GetDraftDir(),
+			rootDir,
 			strings.Join(finalTags, "/"))
 	} else {
-		fmt.Printf("\n📁 目录结构预览: → %s/\n", config.GetDraftDir())
+		fmt.Printf("\n📁 目录结构预览: → %s/\n", rootDir)
 	}
 
 	return info, nil
 }
 
-// 获取已存在的完整标签路径（从blogs和_draft目录）
-func getExistingTags() []string {
-	tagPaths := make(map[string]bool)
-
-	// 扫描blogs目录
-	scanTagPathsRecursively(config.GetBlogsDir(), "", tagPaths)
-
-	// 扫描_draft目录
-	scanTagPathsRecursively(config.GetDraftDir(), "", tagPaths)
-
-	// 转换为切片并排序
-	result := make([]string, 0, len(tagPaths))
-	for tagPath := range tagPaths {
-		if tagPath != "" {
-			result = append(result, tagPath)
-		}
-	}
-
-	return result
-} // 递归扫描目录获取完整标签路径
-func scanTagPathsRecursively(dirPath string, currentPath string, tagPaths map[string]bool) {
-	if _, err := os.Stat(dirPath); os.IsNotExist(err) {
-		return
-	}
-
-	dirs, err := os.ReadDir(dirPath)
-	if err != nil {
-		return
-	}
-
-	hasSubDirs := false
-	for _, dir := range dirs {
-		if dir.IsDir() {
-			hasSubDirs = true
-			var newPath string
-			if currentPath == "" {
-				newPath = dir.Name()
-			} else {
-				newPath = currentPath + "/" + dir.Name()
-			}
-
-			// 递归扫描子目录
-			scanTagPathsRecursively(filepath.Join(dirPath, dir.Name()), newPath, tagPaths)
-		}
-	}
-
-	// 如果当前目录没有子目录，或者包含.md文件，则认为是一个完整的标签路径
-	if currentPath != "" && (!hasSubDirs || containsMarkdownFiles(dirPath)) {
-		tagPaths[currentPath] = true
-	}
-}
-
-// 检查目录是否包含markdown文件
-func containsMarkdownFiles(dirPath string) bool {
-	files, err := os.ReadDir(dirPath)
-	if err != nil {
-		return false
+// selectTemplateInteractively 模板目录中有文章模板时交互式选择，没有模板时返回空字符串，
+// 选择内置格式时返回 article.BuiltinTemplate
+func selectTemplateInteractively() (string, error) {
+	templates, err := articleRepository().Templates()
+	if err != nil || len(templates) == 0 {
+		return "", err
 	}
 
-	for _, file := range files {
-		if !file.IsDir() && strings.HasSuffix(strings.ToLower(file.Name()), ".md") {
-			return true
+	const builtin = "内置格式"
+	options := append([]string{builtin}, templates...)
+	defaultOption := builtin
+	for _, name := range templates {
+		if name == article.DefaultTemplate {
+			defaultOption = name
 		}
 	}
-	return false
-}
-
-func createDraft(title string, tags []string) (string, error) {
-	// 构建目录路径
-	var dirPath string
-	if len(tags) > 0 {
-		// 使用标签作为目录结构：_draft/tag1/tag2/...
-		tagPath := strings.Join(tags, string(filepath.Separator))
-		dirPath = filepath.Join(config.GetDraftDir(), tagPath)
-	} else {
-		// 如果没有标签，直接放在草稿目录下
-		dirPath = config.GetDraftDir()
-	}
-
-	// 确保目录存在
-	if err := os.MkdirAll(dirPath, 0755); err != nil {
-		return "", fmt.Errorf("创建目录失败: %v", err)
-	}
-
-	// 生成文件名
-	fileName := sanitizeFileName(title) + ".md"
-	filePath := filepath.Join(dirPath, fileName)
-
-	// 检查文件是否已存在
-	if _, err := os.Stat(filePath); err == nil {
-		return "", fmt.Errorf("文件已存在: %s", filePath)
-	}
-
-	// 创建文件内容
-	content := generateMarkdownContent(title, tags)
 
-	// 写入文件
-	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
-		return "", fmt.Errorf("写入文件失败: %v", err)
+	var choice string
+	templateQuestion := &survey.Select{
+		Message: "请选择文章模板:",
+		Options: options,
+		Default: defaultOption,
+		Help:    fmt.Sprintf("模板位于 %s 目录中，也可以通过 --template 指定", config.GetTemplateDir()),
 	}
-
-	return filePath, nil
-}
-
-func sanitizeFileName(title string) string {
-	// 将标题转换为适合作为文件名的格式
-	fileName := strings.ToLower(title)
-
-	// 替换空格和特殊字符为下划线
-	specialChars := []string{" ", "/", "\\", ":", "*", "?", "\"", "<", ">", "|", "。", "，", "！", "？", "；", "："}
-	for _, char := range specialChars {
-		fileName = strings.ReplaceAll(fileName, char, "_")
-	}
-
-	// 移除连续的下划线
-	for strings.Contains(fileName, "__") {
-		fileName = strings.ReplaceAll(fileName, "__", "_")
-	}
-
-	// 移除开头和结尾的下划线
-	fileName = strings.Trim(fileName, "_")
-
-	// 如果文件名为空，使用默认名称
-	if fileName == "" {
-		fileName = "untitled"
+	if err := survey.AskOne(templateQuestion, &choice); err != nil {
+		return "", err
 	}
 
-	return fileName
-}
-
-func generateMarkdownContent(title string, tags []string) string {
-	now := time.Now()
-
-	// 构建标签数组字符串
-	var tagStr string
-	if len(tags) > 0 {
-		tagList := make([]string, len(tags))
-		for i, tag := range tags {
-			tagList[i] = fmt.Sprintf(`"%s"`, tag)
-		}
-		tagStr = strings.Join(tagList, ", ")
+	if choice == builtin {
+		return article.BuiltinTemplate, nil
 	}
-
-	content := fmt.Sprintf(`---
-title: "%s"
-date: %s
-tags: [%s]
----
-
-# %s
-
-在这里开始写你的文章内容...
-
-## 介绍
-
-简要介绍文章的主要内容。
-
-## 主要内容
-
-更多内容...
-
-## 总结
-
-总结你的观点和主要内容。
-
----
-
-> 更新时间: %s
-`, title, now.Format("2006-01-02T15:04:05Z07:00"), tagStr, title, now.Format("2006年01月02日 15:04"))
-
-	return content
+	return choice, nil
 }
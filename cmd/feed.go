@@ -0,0 +1,412 @@
+package cmd
+
+import (
+	"MyBlog/internal/config"
+	"MyBlog/internal/fsys"
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	feedOutDir string
+)
+
+var FeedCmd = &cobra.Command{
+	Use:   "feed",
+	Short: "生成RSS 2.0和Atom订阅源",
+	Long: `根据已发布的文章生成RSS 2.0 (feed.xml) 和 Atom (atom.xml) 订阅源。
+
+该命令会：
+1. 扫描blogs目录中的所有文章，按发布时间从新到旧排序
+2. 生成全站订阅源 feed.xml 和 atom.xml
+3. 为每个一级标签生成单独的订阅源 feeds/<标签>/feed.xml 和 atom.xml
+
+订阅源中的链接指向 build 命令生成的文章页面，需要先设置站点地址：
+  myblog config set site.base_url https://example.com/blog/
+
+相关配置项：
+  site.base_url     站点地址（必填）
+  site.title        订阅源标题
+  site.description  订阅源简介
+  author            作者
+  feed.limit        每个订阅源最多包含的文章数量（默认20）`,
+	Example: `  myblog feed
+  myblog feed --out public/`,
+	Args: cobra.NoArgs,
+	Run:  runFeedCommand,
+}
+
+func init() {
+	FeedCmd.Flags().StringVarP(&feedOutDir, "out", "o", "public", "订阅源输出目录")
+}
+
+func runFeedCommand(cmd *cobra.Command, args []string) {
+	// 设置颜色输出
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	blue := color.New(color.FgBlue).SprintFunc()
+
+	if config.GetBaseURL() == "" {
+		fmt.Printf("%s 未设置站点地址，请先运行: myblog config set site.base_url <地址>\n", red("错误:"))
+		return
+	}
+
+	fmt.Printf("%s 开始扫描已发布的文章...\n", blue("信息:"))
+
+	articles, err := scanPublishedArticles()
+	if err != nil {
+		fmt.Printf("%s 扫描文章失败: %v\n", red("错误:"), err)
+		logrus.WithError(err).Error("扫描文章失败")
+		return
+	}
+
+	if len(articles) == 0 {
+		fmt.Printf("%s 没有找到已发布的文章\n", yellow("提示:"))
+		return
+	}
+
+	files, err := writeFeeds(articles, feedOutDir)
+	if err != nil {
+		fmt.Printf("%s 生成订阅源失败: %v\n", red("错误:"), err)
+		logrus.WithError(err).Error("生成订阅源失败")
+		return
+	}
+
+	fmt.Printf("%s 成功生成订阅源!\n", green("✓"))
+	for _, file := range files {
+		fmt.Printf("  %s\n", green(file))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"articles_count": len(articles),
+		"files":          len(files),
+	}).Info("订阅源生成成功")
+}
+
+// writeFeeds 生成全站和每个一级标签的订阅源，返回写入的文件列表
+func writeFeeds(articles []GenArticleInfo, outDir string) ([]string, error) {
+	var files []string
+
+	sorted := sortArticlesForFeed(articles)
+	written, err := writeFeedPair(sorted, outDir, "", config.GetSiteTitle())
+	if err != nil {
+		return files, err
+	}
+	files = append(files, written...)
+
+	// 按一级标签分组
+	byTopTag := make(map[string][]GenArticleInfo)
+	var topTags []string
+	for _, article := range sorted {
+		if len(article.Tags) == 0 {
+			continue
+		}
+		topTag := article.Tags[0]
+		if _, ok := byTopTag[topTag]; !ok {
+			topTags = append(topTags, topTag)
+		}
+		byTopTag[topTag] = append(byTopTag[topTag], article)
+	}
+	sort.Strings(topTags)
+
+	for _, topTag := range topTags {
+		title := fmt.Sprintf("%s - %s", config.GetSiteTitle(), topTag)
+		written, err := writeFeedPair(byTopTag[topTag], outDir, "feeds/"+topTag+"/", title)
+		if err != nil {
+			return files, err
+		}
+		files = append(files, written...)
+	}
+
+	return files, nil
+}
+
+// sortArticlesForFeed 按发布时间从新到旧排序，排除草稿和不公开列出的文章
+//
+// 没有 published 和 date 的文章使用文件的修改时间，无法获取时跳过并给出警告。
+func sortArticlesForFeed(articles []GenArticleInfo) []GenArticleInfo {
+	repo := articleRepository()
+	sorted := make([]GenArticleInfo, 0, len(articles))
+	for _, article := range articles {
+		if !isPublicArticle(article) {
+			continue
+		}
+		if article.Published.IsZero() {
+			info, err := repo.Stat(article.FilePath)
+			if err != nil {
+				logrus.WithError(err).WithField("path", article.FilePath).Warn("文章没有发布时间，已从订阅源中跳过")
+				continue
+			}
+			logrus.WithField("path", article.FilePath).Warn("文章没有发布时间，使用文件的修改时间")
+			article.Published = info.ModTime()
+		}
+		if article.Updated.IsZero() {
+			article.Updated = article.Published
+		}
+		sorted = append(sorted, article)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Published.After(sorted[j].Published)
+	})
+	return sorted
+}
+
+// writeFeedPair 在 dir 下写入 feed.xml 和 atom.xml
+func writeFeedPair(articles []GenArticleInfo, outDir, dir, title string) ([]string, error) {
+	if limit := config.GetFeedLimit(); len(articles) > limit {
+		articles = articles[:limit]
+	}
+
+	rss := buildRSSFeed(articles, dir, title)
+	atom := buildAtomFeed(articles, dir, title)
+
+	var files []string
+	for name, feed := range map[string]interface{}{"feed.xml": rss, "atom.xml": atom} {
+		content, err := xml.MarshalIndent(feed, "", "  ")
+		if err != nil {
+			return files, fmt.Errorf("生成 %s 失败: %v", name, err)
+		}
+
+		filePath := filepath.Join(outDir, filepath.FromSlash(dir), name)
+		if err := fsys.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return files, fmt.Errorf("创建目录失败: %v", err)
+		}
+		if err := fsys.WriteFile(filePath, append([]byte(xml.Header), content...), 0644); err != nil {
+			return files, fmt.Errorf("写入 %s 失败: %v", filePath, err)
+		}
+		files = append(files, filePath)
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Description string   `xml:"description,omitempty"`
+	Categories  []string `xml:"category"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	ID       string      `xml:"id"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Author   *atomPerson `xml:"author,omitempty"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Link       atomLink       `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Summary    string         `xml:"summary,omitempty"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+func buildRSSFeed(articles []GenArticleInfo, dir, title string) *rssFeed {
+	baseURL := config.GetBaseURL()
+
+	feed := &rssFeed{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         title,
+			Link:          baseURL,
+			Description:   feedDescription(),
+			Language:      "zh-CN",
+			LastBuildDate: time.Now().Format(time.RFC1123Z),
+			AtomLink: atomLink{
+				Href: baseURL + escapeURLPath(dir+"feed.xml"),
+				Rel:  "self",
+				Type: "application/rss+xml",
+			},
+		},
+	}
+
+	for _, article := range articles {
+		link := articleURL(article)
+		item := rssItem{
+			Title:       article.Title,
+			Link:        link,
+			GUID:        rssGUID{IsPermaLink: true, Value: link},
+			PubDate:     article.Published.Format(time.RFC1123Z),
+			Description: article.Summary,
+		}
+		if len(article.Tags) > 0 {
+			item.Categories = []string{strings.Join(article.Tags, "/")}
+		}
+		feed.Channel.Items = append(feed.Channel.Items, item)
+	}
+
+	return feed
+}
+
+func buildAtomFeed(articles []GenArticleInfo, dir, title string) *atomFeed {
+	baseURL := config.GetBaseURL()
+
+	updated := time.Now()
+	if len(articles) > 0 {
+		updated = articles[0].Updated
+		for _, article := range articles {
+			if article.Updated.After(updated) {
+				updated = article.Updated
+			}
+		}
+	}
+
+	feed := &atomFeed{
+		Title:    title,
+		Subtitle: config.GetSiteDescription(),
+		ID:       baseURL + escapeURLPath(dir),
+		Updated:  updated.Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: baseURL + escapeURLPath(dir+"atom.xml"), Rel: "self", Type: "application/atom+xml"},
+			{Href: baseURL, Rel: "alternate", Type: "text/html"},
+		},
+	}
+	if author := config.GetAuthor(); author != "" {
+		feed.Author = &atomPerson{Name: author}
+	}
+
+	for _, article := range articles {
+		link := articleURL(article)
+		entry := atomEntry{
+			Title:     article.Title,
+			ID:        link,
+			Link:      atomLink{Href: link, Rel: "alternate", Type: "text/html"},
+			Published: article.Published.Format(time.RFC3339),
+			Updated:   article.Updated.Format(time.RFC3339),
+			Summary:   article.Summary,
+		}
+		if len(article.Tags) > 0 {
+			entry.Categories = []atomCategory{{Term: strings.Join(article.Tags, "/")}}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return feed
+}
+
+// feedDescription RSS要求channel必须有description，未配置时使用站点标题
+func feedDescription() string {
+	if description := config.GetSiteDescription(); description != "" {
+		return description
+	}
+	return config.GetSiteTitle()
+}
+
+// articleURL 文章页面的完整地址
+func articleURL(article GenArticleInfo) string {
+	return config.GetBaseURL() + escapeURLPath(articlePagePath(article))
+}
+
+var (
+	summaryLinkRegex   = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	summaryMarkupRegex = regexp.MustCompile("[*_`~]+")
+)
+
+// summaryMaxRunes 自动摘要的最大长度
+const summaryMaxRunes = 200
+
+// extractSummary 取正文的第一个普通段落作为摘要，去掉Markdown标记
+func extractSummary(body string) string {
+	var paragraph []string
+	inCodeBlock := false
+
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			continue
+		}
+
+		if trimmed == "" {
+			if len(paragraph) > 0 {
+				break
+			}
+			continue
+		}
+
+		// 跳过标题、引用、分隔线、列表和表格
+		if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ">") ||
+			strings.HasPrefix(trimmed, "---") || strings.HasPrefix(trimmed, "|") ||
+			strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ") {
+			if len(paragraph) > 0 {
+				break
+			}
+			continue
+		}
+
+		paragraph = append(paragraph, trimmed)
+	}
+
+	summary := strings.Join(paragraph, " ")
+	summary = summaryLinkRegex.ReplaceAllString(summary, "$1")
+	summary = summaryMarkupRegex.ReplaceAllString(summary, "")
+	summary = strings.TrimSpace(summary)
+
+	runes := []rune(summary)
+	if len(runes) > summaryMaxRunes {
+		summary = string(runes[:summaryMaxRunes]) + "…"
+	}
+
+	return summary
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// TestFeedWithoutDates 没有 published 和 date 的文章使用文件的修改时间，无法获取时跳过
+func TestFeedWithoutDates(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	useRepositoryFS(t, fs, false)
+
+	dated := filepath.Join("blogs", "Go", "有日期.md")
+	undated := filepath.Join("blogs", "Go", "没有日期.md")
+	if err := afero.WriteFile(fs, dated, []byte("---\ntitle: 有日期\ntags: [Go]\npublished: 2026-01-01T09:00:00Z\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := afero.WriteFile(fs, undated, []byte("---\ntitle: 没有日期\ntags: [Go]\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := fs.Chtimes(undated, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	articles, err := scanPublishedArticles()
+	if err != nil {
+		t.Fatalf("scanPublishedArticles: %v", err)
+	}
+	articles = append(articles, GenArticleInfo{Title: "已删除", Tags: []string{"Go"}, FilePath: filepath.Join("blogs", "Go", "已删除.md")})
+
+	sorted := sortArticlesForFeed(articles)
+	if len(sorted) != 2 {
+		t.Fatalf("订阅源中有 %d 篇文章, want 2", len(sorted))
+	}
+	if sorted[0].Title != "没有日期" || !sorted[0].Published.Equal(mtime) || !sorted[0].Updated.Equal(mtime) {
+		t.Errorf("没有日期的文章 = %s, published %s, updated %s", sorted[0].Title, sorted[0].Published, sorted[0].Updated)
+	}
+
+	rss := buildRSSFeed(sorted, "", "博客")
+	atom := buildAtomFeed(sorted, "", "博客")
+	for _, date := range []string{rss.Channel.Items[0].PubDate, atom.Entries[0].Published, atom.Entries[0].Updated} {
+		if strings.Contains(date, "0001") {
+			t.Errorf("订阅源中出现零时间: %s", date)
+		}
+	}
+}
@@ -1,8 +1,8 @@
 package cmd
 
 import (
-	"MyBlog/internal/config"
-	"bufio"
+	"MyBlog/internal/article"
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -13,11 +13,15 @@ import (
 	"github.com/fatih/color"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var (
-	genVerbose bool
+	genVerbose        bool
+	genExportTemplate bool
+	genCheck          bool
+	genLayout         string
+	genMaxDepth       int
+	genPerDir         bool
 )
 
 var GenCmd = &cobra.Command{
@@ -29,8 +33,30 @@ var GenCmd = &cobra.Command{
 1. 扫描blogs目录中的所有文章
 2. 解析文章的Front Matter获取标题和标签信息
 3. 按标签分类整理所有文章
-4. 生成包含快速导航和文章分类的README.md文档`,
+4. 使用README模板生成包含快速导航和文章分类的README.md文档
+
+README 模板为模板目录中的 readme.md.tmpl (Go text/template)，不存在时使用内置模板。
+使用 --export-template 可以将内置模板导出到模板目录中修改。
+
+README.md 中有 <!-- myblog:start 名称 --> 和 <!-- myblog:end 名称 --> 标记时只更新
+标记之间的内容，名称为 nav、stats、articles 或 index (省略名称时为 index)，标记外
+手写的内容保持不变。没有标记时重新生成整个 README.md。
+
+文章分类默认每个标签路径一个标题 (flat)。--layout tree 按标签树展示，Go/并发 显示为
+Go 下的子标签，每层标题中包括子标签在内的文章总数，例如 Go (23)；--layout details
+使用可折叠的 <details> 列表；--max-depth 限制标签树的层数。
+
+使用 --per-dir 同时在博客目录的每个标签目录中生成索引文件 README.md，列出该目录中的
+文章和子标签目录，并带有返回上级目录和根目录 README.md 的导航。不再包含文章的目录中
+遗留的索引文件会被删除；索引文件以 <!-- myblog:index --> 开头，不会被当作文章。
+
+使用 --check 只检查 README.md 是否需要更新而不写入，需要更新时以状态码 1 退出，
+可以在 CI 中使用。`,
 	Example: `  myblog gen
+  myblog gen --export-template
+  myblog gen --check
+  myblog gen --layout details --max-depth 2
+  myblog gen --per-dir
   myblog gen --verbose`,
 	Args: cobra.NoArgs,
 	Run:  runGenCommand,
@@ -39,6 +65,12 @@ var GenCmd = &cobra.Command{
 func init() {
 	// 添加命令行标志
 	GenCmd.Flags().BoolVarP(&genVerbose, "verbose", "v", false, "详细输出")
+	GenCmd.Flags().BoolVar(&genExportTemplate, "export-template", false, "将内置的README模板导出到模板目录")
+	GenCmd.Flags().StringVar(&genLayout, "layout", "", "文章分类的布局：tree、details 或 flat（默认使用 readme.layout 配置）")
+	GenCmd.Flags().IntVar(&genMaxDepth, "max-depth", 0, "标签树的最大层数，更深的文章归入上层标签（默认使用 readme.max_depth 配置）")
+	GenCmd.Flags().BoolVar(&genPerDir, "per-dir", false, "同时在博客目录的每个标签目录中生成索引文件 README.md")
+	GenCmd.Flags().BoolVar(&genCheck, "check", false, "只检查README.md是否需要更新，需要更新时以状态码1退出")
+	addCommitFlag(GenCmd)
 
 	// 设置日志级别
 	if genVerbose {
@@ -50,9 +82,16 @@ type GenArticleInfo struct {
 	Title        string    `yaml:"title"`
 	Date         time.Time `yaml:"date"`
 	Published    time.Time `yaml:"published"`
+	PublishAt    time.Time `yaml:"publish_at"` // 定时发布时间
+	Updated      time.Time `yaml:"updated"`
 	Tags         []string  `yaml:"tags"`
+	Summary      string    `yaml:"summary"`
 	FilePath     string    `yaml:"-"`
 	RelativePath string    `yaml:"-"`
+	Draft        bool      `yaml:"draft"`    // Front Matter 中标记为草稿
+	Unlisted     bool      `yaml:"unlisted"` // 不出现在列表、订阅源和站点地图中
+	FromDrafts   bool      `yaml:"-"`        // 文章位于草稿目录中
+	Slug         string    `yaml:"slug"`     // 页面地址中使用的名称，为空时根据文件名生成
 }
 
 type TagGroup struct {
@@ -65,6 +104,98 @@ func runGenCommand(cmd *cobra.Command, args []string) {
 	green := color.New(color.FgGreen).SprintFunc()
 	red := color.New(color.FgRed).SprintFunc()
 	yellow := color.New(color.FgYellow).SprintFunc()
+
+	if genExportTemplate {
+		templatePath, err := exportReadmeTemplate()
+		if err != nil {
+			fmt.Printf("%s 导出README模板失败: %v\n", red("错误:"), err)
+			logrus.WithError(err).Error("导出README模板失败")
+			return
+		}
+		fmt.Printf("%s 成功导出README模板!\n", green("✓"))
+		fmt.Printf("  文件路径: %s\n", green(templatePath))
+		logrus.WithField("path", templatePath).Info("README模板导出成功")
+		return
+	}
+
+	if genCheck {
+		articles, tagGroups, err := scanGenArticles()
+		if err != nil {
+			fmt.Printf("%s 扫描文章失败: %v\n", red("错误:"), err)
+			logrus.WithError(err).Error("扫描文章失败")
+			os.Exit(1)
+		}
+
+		_, changed, err := readmeContent(tagGroups)
+		if err != nil {
+			fmt.Printf("%s 生成README.md失败: %v\n", red("错误:"), err)
+			logrus.WithError(err).Error("生成README.md失败")
+			os.Exit(1)
+		}
+		stale := changed
+		if changed {
+			fmt.Printf("%s README.md 不是最新的\n", red("错误:"))
+		}
+
+		if genPerDir {
+			writes, removes, err := dirIndexChanges(articles)
+			if err != nil {
+				fmt.Printf("%s 生成索引文件失败: %v\n", red("错误:"), err)
+				logrus.WithError(err).Error("生成索引文件失败")
+				os.Exit(1)
+			}
+			stalePaths := make([]string, 0, len(writes))
+			for indexPath := range writes {
+				stalePaths = append(stalePaths, indexPath)
+			}
+			sort.Strings(stalePaths)
+			for _, indexPath := range stalePaths {
+				fmt.Printf("%s 索引文件不是最新的: %s\n", red("错误:"), indexPath)
+			}
+			for _, indexPath := range removes {
+				fmt.Printf("%s 遗留的索引文件: %s\n", red("错误:"), indexPath)
+			}
+			stale = stale || len(writes) > 0 || len(removes) > 0
+		}
+
+		if stale {
+			fmt.Printf("%s 请运行 myblog gen 重新生成\n", yellow("提示:"))
+			os.Exit(1)
+		}
+		fmt.Printf("%s README.md 已是最新\n", green("✓"))
+		return
+	}
+
+	if !beginCommit() {
+		return
+	}
+
+	result, err := regenerateIndexes(genPerDir)
+	if err != nil {
+		return
+	}
+	if !result.changed() {
+		return
+	}
+	autoCommit("gen", []string{genCommitTitle(result.articles, result.readmeChanged, result.written, result.removed)})
+}
+
+// genResult 重新生成 README.md 和目录索引的结果
+type genResult struct {
+	articles      int
+	readmeChanged bool
+	written       []string
+	removed       []string
+}
+
+// changed 是否修改了文件
+func (r genResult) changed() bool {
+	return r.readmeChanged || len(r.written) > 0 || len(r.removed) > 0
+}
+
+// scanGenArticles 扫描已发布的文章并按标签分组，输出扫描结果
+func scanGenArticles() ([]GenArticleInfo, []TagGroup, error) {
+	yellow := color.New(color.FgYellow).SprintFunc()
 	blue := color.New(color.FgBlue).SprintFunc()
 
 	fmt.Printf("%s 开始扫描已发布的文章...\n", blue("信息:"))
@@ -72,153 +203,177 @@ func runGenCommand(cmd *cobra.Command, args []string) {
 	// 扫描blogs目录获取所有文章
 	articles, err := scanPublishedArticles()
 	if err != nil {
-		fmt.Printf("%s 扫描文章失败: %v\n", red("错误:"), err)
-		logrus.WithError(err).Error("扫描文章失败")
-		return
+		return nil, nil, err
 	}
 
+	// 没有文章时也要生成空的索引，--check 同样需要比较
 	if len(articles) == 0 {
 		fmt.Printf("%s 没有找到已发布的文章\n", yellow("提示:"))
-		return
+	} else {
+		fmt.Printf("%s 找到 %d 篇已发布的文章\n", blue("信息:"), len(articles))
 	}
 
-	fmt.Printf("%s 找到 %d 篇已发布的文章\n", blue("信息:"), len(articles))
-
 	// 按标签分组文章
 	tagGroups := groupArticlesByTags(articles)
 
 	fmt.Printf("%s 按标签分组完成，共 %d 个标签分类\n", blue("信息:"), len(tagGroups))
+	return articles, tagGroups, nil
+}
+
+// regenerateIndexes 扫描已发布的文章并重新生成 README.md，perDir 为 true 时同时更新每个标签目录的索引文件
+//
+// gen 和定时发布共用，错误已经输出，返回的结果中包括出错之前修改过的文件。
+func regenerateIndexes(perDir bool) (genResult, error) {
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	var result genResult
+	articles, tagGroups, err := scanGenArticles()
+	if err != nil {
+		fmt.Printf("%s 扫描文章失败: %v\n", red("错误:"), err)
+		logrus.WithError(err).Error("扫描文章失败")
+		return result, err
+	}
+	result.articles = len(articles)
 
 	// 生成README.md
-	err = generateReadme(tagGroups)
+	result.readmeChanged, err = generateReadme(tagGroups)
 	if err != nil {
 		fmt.Printf("%s 生成README.md失败: %v\n", red("错误:"), err)
 		logrus.WithError(err).Error("生成README.md失败")
-		return
+		return result, err
 	}
 
-	fmt.Printf("%s 成功生成README.md文档!\n", green("✓"))
-	fmt.Printf("  文章总数: %s\n", yellow(fmt.Sprintf("%d", len(articles))))
-	fmt.Printf("  标签分类: %s\n", yellow(fmt.Sprintf("%d", len(tagGroups))))
-	fmt.Printf("  文件路径: %s\n", green("README.md"))
+	if perDir {
+		result.written, result.removed, err = generateDirIndexes(articles)
+		if err != nil {
+			fmt.Printf("%s 生成索引文件失败: %v\n", red("错误:"), err)
+			logrus.WithError(err).Error("生成索引文件失败")
+			return result, err
+		}
+	}
+
+	if !result.readmeChanged {
+		fmt.Printf("%s README.md 已是最新，无需更新\n", green("✓"))
+	} else {
+		fmt.Printf("%s 成功生成README.md文档!\n", green("✓"))
+		fmt.Printf("  文章总数: %s\n", yellow(fmt.Sprintf("%d", len(articles))))
+		fmt.Printf("  标签分类: %s\n", yellow(fmt.Sprintf("%d", len(tagGroups))))
+		fmt.Printf("  文件路径: %s\n", green("README.md"))
+	}
+	if perDir {
+		fmt.Printf("%s 索引文件: 更新 %s 个，删除 %s 个\n", green("✓"),
+			yellow(fmt.Sprintf("%d", len(result.written))), yellow(fmt.Sprintf("%d", len(result.removed))))
+		for _, indexPath := range result.written {
+			fmt.Printf("  更新: %s\n", green(indexPath))
+		}
+		for _, indexPath := range result.removed {
+			fmt.Printf("  删除: %s\n", yellow(indexPath))
+		}
+	}
+	if !result.changed() {
+		return result, nil
+	}
 
 	logrus.WithFields(logrus.Fields{
-		"articles_count": len(articles),
-		"tag_groups":     len(tagGroups),
+		"articles_count":  len(articles),
+		"tag_groups":      len(tagGroups),
+		"readme_changed":  result.readmeChanged,
+		"indexes_written": len(result.written),
+		"indexes_removed": len(result.removed),
 	}).Info("README.md生成成功")
+	return result, nil
 }
 
-func scanPublishedArticles() ([]GenArticleInfo, error) {
-	var articles []GenArticleInfo
-	blogsDir := config.GetBlogsDir()
-
-	// 检查blogs目录是否存在
-	if _, err := os.Stat(blogsDir); os.IsNotExist(err) {
-		// 目录不存在，返回空切片而不是错误
-		return articles, nil
+// genCommitTitle 自动提交时说明本次 gen 更新了哪些文件，写入和删除的索引文件已由 fsys 记录
+func genCommitTitle(articleCount int, readmeChanged bool, written, removed []string) string {
+	var parts []string
+	if readmeChanged {
+		parts = append(parts, "README.md")
 	}
+	if len(written) > 0 {
+		parts = append(parts, fmt.Sprintf("%d 个目录索引", len(written)))
+	}
+	title := fmt.Sprintf("更新文章索引（%d 篇文章）", articleCount)
+	if len(parts) > 0 {
+		title = fmt.Sprintf("更新 %s（%d 篇文章）", strings.Join(parts, " 和 "), articleCount)
+	}
+	if len(removed) > 0 {
+		title += fmt.Sprintf("，删除 %d 个目录索引", len(removed))
+	}
+	return title
+}
 
-	err := filepath.Walk(blogsDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		// 只处理.md文件
-		if !info.IsDir() && strings.HasSuffix(strings.ToLower(info.Name()), ".md") {
-			article, err := parseArticle(path)
-			if err != nil {
-				logrus.WithError(err).Warnf("解析文章失败: %s", path)
-				return nil // 继续处理其他文件，不中断整个过程
-			}
-			
-			// 计算相对路径
-			relPath, err := filepath.Rel(blogsDir, path)
-			if err != nil {
-				relPath = path
-			}
-			article.RelativePath = filepath.ToSlash(relPath)
-			
-			articles = append(articles, *article)
-		}
-
-		return nil
-	})
+func scanPublishedArticles() ([]GenArticleInfo, error) {
+	return scanArticles(article.Published)
+}
 
-	return articles, err
+// scanDraftArticles 扫描草稿目录中的所有文章
+func scanDraftArticles() ([]GenArticleInfo, error) {
+	return scanArticles(article.Draft)
 }
 
-func parseArticle(filePath string) (*GenArticleInfo, error) {
-	file, err := os.Open(filePath)
+// scanArticles 扫描根目录中的所有Markdown文章，RelativePath 相对于该目录
+func scanArticles(status article.Status) ([]GenArticleInfo, error) {
+	list, err := articleRepository().List(status, func(path string, err error) {
+		// 继续处理其他文件，不中断整个过程
+		logrus.WithError(err).Warnf("解析文章失败: %s", path)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("打开文件失败: %v", err)
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	var frontMatterLines []string
-	inFrontMatter := false
-	frontMatterEnd := false
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		
-		if line == "---" {
-			if !inFrontMatter {
-				inFrontMatter = true
-				continue
-			} else {
-				frontMatterEnd = true
-				break
-			}
-		}
-
-		if inFrontMatter {
-			frontMatterLines = append(frontMatterLines, line)
-		}
+		return nil, err
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("读取文件失败: %v", err)
+	var articles []GenArticleInfo
+	for _, a := range list {
+		articles = append(articles, newGenArticleInfo(a))
 	}
+	return articles, nil
+}
 
-	if !frontMatterEnd {
-		return nil, fmt.Errorf("找不到完整的Front Matter")
-	}
+// newGenArticleInfo 转换为生成文档使用的文章信息，缺少的字段使用其他字段补全
+func newGenArticleInfo(a *article.Article) GenArticleInfo {
+	var info GenArticleInfo
+	info.Title = a.Title
+	info.Tags = a.Tags
+	info.Published = a.Published
+	info.PublishAt = a.PublishAt
+	info.Date = a.Date
+	info.Updated = a.Updated
+	info.Summary = a.Summary
+	info.Draft = a.Draft
+	info.Unlisted = a.Unlisted
+	info.Slug = a.Slug
+	info.FilePath = a.Path
+	info.RelativePath = a.RelPath
+	info.FromDrafts = a.Status == article.Draft
 
-	// 解析YAML Front Matter
-	frontMatterContent := strings.Join(frontMatterLines, "\n")
-	
-	// 使用 viper 解析 YAML
-	v := viper.New()
-	v.SetConfigType("yaml")
-	err = v.ReadConfig(strings.NewReader(frontMatterContent))
-	if err != nil {
-		return nil, fmt.Errorf("解析Front Matter失败: %v", err)
+	// 如果published时间为空，使用date时间
+	if info.Published.IsZero() && !info.Date.IsZero() {
+		info.Published = info.Date
 	}
 
-	var article GenArticleInfo
-	article.Title = v.GetString("title")
-	article.Tags = v.GetStringSlice("tags")
-	
-	// 直接从viper获取时间
-	article.Published = v.GetTime("published")
-	article.Date = v.GetTime("date")
-	
-	// 如果published时间为空，使用date时间
-	if article.Published.IsZero() && !article.Date.IsZero() {
-		article.Published = article.Date
+	// 如果updated时间为空，使用published时间
+	if info.Updated.IsZero() {
+		info.Updated = info.Published
 	}
 
+	// 如果没有摘要，使用正文的第一段
+	if info.Summary == "" {
+		info.Summary = extractSummary(a.Body)
+	}
 
-	article.FilePath = filePath
-	
 	// 如果标题为空，使用文件名作为标题
-	if article.Title == "" {
-		filename := filepath.Base(filePath)
-		article.Title = strings.TrimSuffix(filename, filepath.Ext(filename))
+	if info.Title == "" {
+		info.Title = a.Name()
 	}
 
-	return &article, nil
+	return info
+}
+
+// isPublicArticle 判断文章是否可以公开列出（出现在订阅源、站点地图等位置）
+func isPublicArticle(article GenArticleInfo) bool {
+	return !article.FromDrafts && !article.Draft && !article.Unlisted
 }
 
 func groupArticlesByTags(articles []GenArticleInfo) []TagGroup {
@@ -243,7 +398,7 @@ func groupArticlesByTags(articles []GenArticleInfo) []TagGroup {
 		sort.Slice(articles, func(i, j int) bool {
 			return articles[i].Published.After(articles[j].Published)
 		})
-		
+
 		tagGroups = append(tagGroups, TagGroup{
 			TagPath:  tagPath,
 			Articles: articles,
@@ -258,94 +413,42 @@ func groupArticlesByTags(articles []GenArticleInfo) []TagGroup {
 	return tagGroups
 }
 
-func generateReadme(tagGroups []TagGroup) error {
-	var content strings.Builder
-
-	// 写入项目介绍
-	content.WriteString(`# MyBlog - 简易静态博客系统
-
-MyBlog 是一个简易的静态博客系统，类似于 Hugo，专为命令行使用而设计。它借助 GitHub 对 Markdown 文档的完美支持，让 Markdown 文档成为你的界面。
-
-## 特性
-
-- 🚀 **简单易用**: 基于命令行的简洁界面
-- 📝 **Markdown 支持**: 完美支持 Markdown 格式
-- 🏷️ **标签和分类**: 支持文章标签和分类管理
-- 🎨 **交互式命令**: 美观的交互式命令行界面
-- 🌈 **彩色输出**: 支持彩色终端输出
-- 📁 **自动组织**: 自动创建和组织文件结构
-
-## 快速开始
-
-### 构建项目
-
-`)
-	content.WriteString("```bash\n")
-	content.WriteString("go build -o myblog .\n")
-	content.WriteString("```\n\n")
-
-	// 添加帮助文档链接
-	content.WriteString(`### 使用帮助
-
-详细使用说明请参考：[使用帮助文档](help.md)
-
-`)
-
-	// 生成文章标签快速导航
-	content.WriteString("## 📚 文章导航\n\n")
-	
-	// 生成目录
-	content.WriteString("### 标签分类快速跳转\n\n")
-	for _, group := range tagGroups {
-		anchorLink := strings.ToLower(strings.ReplaceAll(group.TagPath, "/", "-"))
-		anchorLink = strings.ReplaceAll(anchorLink, " ", "-")
-		content.WriteString(fmt.Sprintf("- [%s](#%s) (%d篇)\n", group.TagPath, anchorLink, len(group.Articles)))
-	}
-	content.WriteString("\n")
-
-	// 统计信息
-	totalArticles := 0
-	for _, group := range tagGroups {
-		totalArticles += len(group.Articles)
-	}
-	
-	content.WriteString(fmt.Sprintf("**📊 统计信息**: 共 %d 个标签分类，%d 篇文章\n\n", len(tagGroups), totalArticles))
-	content.WriteString("---\n\n")
-
-	// 按标签分类展示文章
-	content.WriteString("## 📖 文章分类\n\n")
-
-	for _, group := range tagGroups {
-		// 标签分类标题
-		content.WriteString(fmt.Sprintf("### %s\n\n", group.TagPath))
-		
-		// 文章列表
-		for _, article := range group.Articles {
-			publishedTime := article.Published.Format("2006-01-02")
-			
-			// 生成GitHub相对路径链接
-			articleLink := fmt.Sprintf("blogs/%s", article.RelativePath)
-			
-			content.WriteString(fmt.Sprintf("- [%s](%s) - *%s*\n", 
-				article.Title, 
-				articleLink, 
-				publishedTime))
-		}
-		
-		content.WriteString("\n")
-	}
-
-	// 添加项目信息
-	content.WriteString("---\n\n")
-	content.WriteString("## 可用命令\n\n")
-	content.WriteString("- `draft` - 创建草稿文章\n")
-	content.WriteString("- `new` - 创建正式文章\n")
-	content.WriteString("- `pub` - 发布草稿到正式文章\n")
-	content.WriteString("- `gen` - 生成README.md文档\n\n")
-	
-	// 生成时间
-	content.WriteString(fmt.Sprintf("*README.md 生成时间: %s*\n", time.Now().Format("2006-01-02 15:04:05")))
-
-	// 写入文件
-	return os.WriteFile("README.md", []byte(content.String()), 0644)
+// readmeContent 生成新的 README.md 内容，并判断与当前的 README.md 是否不同
+func readmeContent(tagGroups []TagGroup) ([]byte, bool, error) {
+	existing, err := articleRepository().ReadFile("README.md")
+	missing := os.IsNotExist(err)
+	if err != nil && !missing {
+		return nil, false, fmt.Errorf("读取README.md失败: %v", err)
+	}
+
+	content, err := renderReadme(existing, tagGroups)
+	if err != nil {
+		return nil, false, err
+	}
+	return content, missing || !bytes.Equal(existing, content), nil
+}
+
+// generateReadme 使用 README 模板生成 README.md，内容没有变化时不写入并返回 false
+func generateReadme(tagGroups []TagGroup) (bool, error) {
+	content, changed, err := readmeContent(tagGroups)
+	if err != nil || !changed {
+		return false, err
+	}
+	return true, articleRepository().WriteFile("README.md", content, 0644)
+}
+
+// exportReadmeTemplate 将内置的 README 模板写入模板目录，已存在时不覆盖
+func exportReadmeTemplate() (string, error) {
+	templatePath := readmeTemplatePath()
+	repo := articleRepository()
+	if _, err := repo.Stat(templatePath); err == nil {
+		return "", fmt.Errorf("模板文件已存在: %s", templatePath)
+	}
+	if err := repo.MkdirAll(filepath.Dir(templatePath), 0755); err != nil {
+		return "", fmt.Errorf("创建模板目录失败: %v", err)
+	}
+	if err := repo.WriteFile(templatePath, []byte(defaultReadmeTemplate), 0644); err != nil {
+		return "", fmt.Errorf("写入模板文件失败: %v", err)
+	}
+	return templatePath, nil
 }
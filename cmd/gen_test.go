@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"MyBlog/internal/article"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+// useRepositoryFS 让命令使用 fs 中的文章仓库，测试结束后恢复
+func useRepositoryFS(t *testing.T, fs afero.Fs, directWrites bool) {
+	t.Helper()
+	previous := newRepository
+	newRepository = func(draftDir, blogsDir string) *article.Repository {
+		repo := article.NewRepositoryFS(fs, draftDir, blogsDir)
+		repo.DirectWrites = directWrites
+		return repo
+	}
+	t.Cleanup(func() { newRepository = previous })
+}
+
+// draftPublishGen 创建草稿、发布并生成 README.md 和目录索引
+func draftPublishGen(t *testing.T, fs afero.Fs) {
+	t.Helper()
+	draftPath, err := articleRepository().Create(article.Draft, article.CreateOptions{Title: "你好", Tags: []string{"Go"}})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	drafts, unresolved := findDrafts([]string{"Go/你好.md"}, "Go")
+	if len(drafts) != 1 || len(unresolved) != 0 {
+		t.Fatalf("findDrafts = %q, %d 个失败", drafts, len(unresolved))
+	}
+	if results := publishDrafts(drafts, nil); results[0].err != nil {
+		t.Fatalf("发布 %s 失败: %v", draftPath, results[0].err)
+	}
+
+	previous := genPerDir
+	genPerDir = true
+	t.Cleanup(func() { genPerDir = previous })
+	runGenCommand(GenCmd, nil)
+
+	readme, err := afero.ReadFile(fs, "README.md")
+	if err != nil {
+		t.Fatalf("没有生成 README.md: %v", err)
+	}
+	if !strings.Contains(string(readme), "你好") {
+		t.Errorf("README.md 中没有文章:\n%s", readme)
+	}
+	index, err := afero.ReadFile(fs, filepath.Join("blogs", "Go", article.IndexFileName))
+	if err != nil {
+		t.Fatalf("没有生成目录索引: %v", err)
+	}
+	if !strings.HasPrefix(string(index), article.IndexMarker) || !strings.Contains(string(index), "你好") {
+		t.Errorf("目录索引:\n%s", index)
+	}
+	if exists, _ := afero.Exists(fs, filepath.Join("_draft", "Go", "你好.md")); exists {
+		t.Error("发布后草稿仍然存在")
+	}
+}
+
+// TestDraftPublishGenInMemory 命令在内存文件系统中完成草稿、发布和生成索引
+func TestDraftPublishGenInMemory(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	fs := afero.NewMemMapFs()
+	useRepositoryFS(t, fs, false)
+
+	draftPublishGen(t, fs)
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("修改了磁盘: %v", entries)
+	}
+}
+
+// TestDraftPublishGenOverlay 以磁盘为只读底层的覆盖层中，修改只写入内存
+func TestDraftPublishGenOverlay(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	readme := "# 我的博客\n"
+	if err := os.WriteFile("README.md", []byte(readme), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fs := afero.NewCopyOnWriteFs(afero.NewReadOnlyFs(afero.NewOsFs()), afero.NewMemMapFs())
+	useRepositoryFS(t, fs, true)
+
+	draftPublishGen(t, fs)
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 || entries[0].Name() != "README.md" {
+		t.Errorf("修改了磁盘: %v", entries)
+	}
+	if content, _ := os.ReadFile("README.md"); string(content) != readme {
+		t.Errorf("磁盘上的 README.md 被修改:\n%s", content)
+	}
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"MyBlog/internal/config"
+	"MyBlog/internal/fsys"
+	"MyBlog/internal/git"
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gitCommit bool
+
+	// commitRepo beginCommit 打开的 git 仓库，不需要提交时为 nil
+	commitRepo *git.Repository
+)
+
+// addCommitFlag 为会修改文章的命令注册 --commit 参数
+func addCommitFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&gitCommit, "commit", false, "完成后将修改过的文件提交到 git (也可以设置 git.autocommit)")
+}
+
+// commitData 提交信息和标签名模板中可用的数据
+type commitData struct {
+	Command string   // 命令名，例如 pub
+	Title   string   // 文章标题，多篇时以逗号分隔
+	Titles  []string // 所有文章标题
+	Count   int      // 文章数量
+	Date    string   // 当前日期 2006-01-02
+	Time    string   // 当前时间 150405
+}
+
+// commitEnabled 是否需要自动提交
+func commitEnabled() bool {
+	return gitCommit || config.GetGitAutoCommit()
+}
+
+// beginCommit 在命令修改文件之前调用，开始记录本次操作修改的文件
+//
+// 需要自动提交时先检查 git 仓库和暂存区，无法提交时返回 false，命令不应修改任何文件，
+// 避免文件已经移动却无法提交。
+func beginCommit() bool {
+	fsys.ResetTouched()
+	commitRepo = nil
+	if !commitEnabled() || fsys.DryRun() {
+		return true
+	}
+
+	red := color.New(color.FgRed).SprintFunc()
+	repo, err := git.Open()
+	if err == nil {
+		err = repo.CheckStaged()
+	}
+	if err != nil {
+		fmt.Printf("%s 无法自动提交，没有修改任何文件: %v\n", red("错误:"), err)
+		logrus.WithError(err).Error("无法自动提交")
+		return false
+	}
+	commitRepo = repo
+	return true
+}
+
+// autoCommit 按 --commit 参数或 git.autocommit 配置提交本次命令修改过的文件
+//
+// 命令修改文件之前必须先调用 beginCommit。
+func autoCommit(command string, titles []string) {
+	if !commitEnabled() {
+		return
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	files := fsys.Touched()
+	if len(files) == 0 {
+		return
+	}
+
+	now := time.Now()
+	data := commitData{
+		Command: command,
+		Title:   strings.Join(titles, ", "),
+		Titles:  titles,
+		Count:   len(titles),
+		Date:    now.Format("2006-01-02"),
+		Time:    now.Format("150405"),
+	}
+
+	message, err := renderCommitTemplate("git.message", config.GetGitMessage(), data)
+	if err != nil {
+		fmt.Printf("%s %v\n", red("错误:"), err)
+		return
+	}
+	tagName, err := renderCommitTemplate("git.tag", config.GetGitTag(), data)
+	if err != nil {
+		fmt.Printf("%s %v\n", red("错误:"), err)
+		return
+	}
+
+	if fsys.DryRun() {
+		fmt.Printf("%s 将提交到 git: %s\n", yellow("[dry-run]"), message)
+		if tagName != "" {
+			fmt.Printf("%s 将创建标签: %s\n", yellow("[dry-run]"), tagName)
+		}
+		return
+	}
+
+	repo := commitRepo
+	if repo == nil {
+		return
+	}
+
+	committed, err := repo.Commit(files, message)
+	if err != nil {
+		fmt.Printf("%s 自动提交失败: %v\n", red("错误:"), err)
+		logrus.WithError(err).Error("自动提交失败")
+		return
+	}
+	if len(committed) == 0 {
+		fmt.Printf("%s 没有需要提交的修改\n", yellow("提示:"))
+		return
+	}
+
+	fmt.Printf("%s 已提交到 git: %s\n", green("✓"), message)
+	for _, file := range committed {
+		fmt.Printf("  %s\n", file)
+	}
+
+	if tagName != "" {
+		if err := repo.Tag(tagName); err != nil {
+			fmt.Printf("%s %v\n", red("错误:"), err)
+			logrus.WithError(err).Error("创建标签失败")
+			return
+		}
+		fmt.Printf("  标签: %s\n", green(tagName))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"message": message,
+		"files":   committed,
+		"tag":     tagName,
+	}).Info("自动提交成功")
+}
+
+// renderCommitTemplate 渲染提交信息或标签名模板
+func renderCommitTemplate(name, text string, data commitData) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("解析 %s 模板失败: %v", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("渲染 %s 模板失败: %v", name, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"MyBlog/internal/article"
+	"MyBlog/internal/config"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// dirIndexData 标签目录索引文件模板中使用的数据
+type dirIndexData struct {
+	Marker string
+	Node   *TagNode
+	// Breadcrumbs 从根目录 README.md 到上一级目录的链接
+	Breadcrumbs []dirIndexLink
+}
+
+// dirIndexLink 索引文件中的链接
+type dirIndexLink struct {
+	Name string
+	Link string
+}
+
+// dirIndexFuncs 索引文件模板中可以使用的函数
+var dirIndexFuncs = template.FuncMap{
+	"date":   readmeFuncs["date"],
+	"escape": article.EscapeDestination,
+	"base":   path.Base,
+}
+
+// dirIndexTemplate 标签目录索引文件的模板
+var dirIndexTemplate = template.Must(template.New("index").Funcs(dirIndexFuncs).Parse(`{{ .Marker }}
+<!-- 由 myblog gen --per-dir 生成，请勿手动修改 -->
+
+{{ range .Breadcrumbs }}[{{ .Name }}]({{ escape .Link }}) / {{ end }}{{ .Node.Name }}
+
+# {{ .Node.Name }}
+
+共 {{ .Node.Count }} 篇文章
+{{- if .Node.Children }}
+
+## 子标签
+{{ range .Node.Children }}
+- [{{ .Name }}]({{ escape .Name }}/README.md) ({{ .Count }}篇)
+{{- end }}
+{{- end }}
+{{- if .Node.Articles }}
+
+## 文章
+{{ range .Node.Articles }}
+- [{{ .Title }}]({{ escape (base .RelativePath) }}) - *{{ date "2006-01-02" .Published }}*
+{{- end }}
+{{- end }}
+`))
+
+// buildDirTree 按文章所在的目录（而不是 Front Matter 中的标签）整理标签树，博客目录根下的文章不属于任何标签目录
+func buildDirTree(articles []GenArticleInfo) []*TagNode {
+	dirMap := make(map[string][]GenArticleInfo)
+	for _, info := range articles {
+		dir := path.Dir(info.RelativePath)
+		if dir == "." {
+			continue
+		}
+		dirMap[dir] = append(dirMap[dir], info)
+	}
+
+	var groups []TagGroup
+	for dir, infos := range dirMap {
+		groups = append(groups, TagGroup{TagPath: dir, Articles: infos})
+	}
+	return buildTagTree(groups, 0)
+}
+
+// renderDirIndexes 生成每个标签目录的索引文件内容，键为索引文件的路径
+func renderDirIndexes(articles []GenArticleInfo) (map[string][]byte, error) {
+	blogsDir := config.GetBlogsDir()
+	absBlogsDir, err := filepath.Abs(blogsDir)
+	if err != nil {
+		return nil, fmt.Errorf("获取博客目录绝对路径失败: %v", err)
+	}
+	workDir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("获取当前目录失败: %v", err)
+	}
+	// 根目录 README.md 相对于博客目录的路径
+	rootLink, err := filepath.Rel(absBlogsDir, workDir)
+	if err != nil {
+		return nil, fmt.Errorf("计算README.md的相对路径失败: %v", err)
+	}
+	rootLink = path.Join(filepath.ToSlash(rootLink), "README.md")
+
+	indexes := make(map[string][]byte)
+	var render func(nodes []*TagNode, parents []*TagNode) error
+	render = func(nodes []*TagNode, parents []*TagNode) error {
+		for _, node := range nodes {
+			up := strings.Repeat("../", node.Depth)
+			data := dirIndexData{
+				Marker:      article.IndexMarker,
+				Node:        node,
+				Breadcrumbs: []dirIndexLink{{Name: "首页", Link: up + rootLink}},
+			}
+			for i, parent := range parents {
+				data.Breadcrumbs = append(data.Breadcrumbs, dirIndexLink{
+					Name: parent.Name,
+					Link: strings.Repeat("../", len(parents)-i) + article.IndexFileName,
+				})
+			}
+
+			var buf bytes.Buffer
+			if err := dirIndexTemplate.Execute(&buf, data); err != nil {
+				return fmt.Errorf("生成 %s 的索引失败: %v", node.TagPath, err)
+			}
+			indexPath := filepath.Join(blogsDir, filepath.FromSlash(node.TagPath), article.IndexFileName)
+			indexes[indexPath] = buf.Bytes()
+
+			if err := render(node.Children, append(parents[:len(parents):len(parents)], node)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := render(buildDirTree(articles), nil); err != nil {
+		return nil, err
+	}
+	return indexes, nil
+}
+
+// dirIndexChanges 找出内容有变化的索引文件，以及不再包含文章的目录中遗留的索引文件
+func dirIndexChanges(articles []GenArticleInfo) (map[string][]byte, []string, error) {
+	indexes, err := renderDirIndexes(articles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	repo := articleRepository()
+	writes := make(map[string][]byte)
+	for indexPath, content := range indexes {
+		existing, err := repo.ReadFile(indexPath)
+		if err == nil && bytes.Equal(existing, content) {
+			continue
+		}
+		// 不覆盖同名的手写 README.md
+		if err == nil && !bytes.HasPrefix(existing, []byte(article.IndexMarker)) {
+			return nil, nil, fmt.Errorf("%s 不是生成的索引文件，不能覆盖", indexPath)
+		}
+		writes[indexPath] = content
+	}
+
+	var removes []string
+	for _, indexPath := range repo.IndexFiles() {
+		if _, ok := indexes[indexPath]; !ok {
+			removes = append(removes, indexPath)
+		}
+	}
+	sort.Strings(removes)
+	return writes, removes, nil
+}
+
+// generateDirIndexes 更新每个标签目录中的索引文件，并删除遗留的索引文件
+func generateDirIndexes(articles []GenArticleInfo) ([]string, []string, error) {
+	writes, removes, err := dirIndexChanges(articles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	written := make([]string, 0, len(writes))
+	for indexPath := range writes {
+		written = append(written, indexPath)
+	}
+	sort.Strings(written)
+
+	repo := articleRepository()
+	for _, indexPath := range written {
+		if err := repo.WriteFile(indexPath, writes[indexPath], 0644); err != nil {
+			return nil, nil, fmt.Errorf("写入索引文件失败: %v", err)
+		}
+	}
+	for _, indexPath := range removes {
+		if err := repo.Remove(indexPath); err != nil {
+			return nil, nil, fmt.Errorf("删除索引文件失败: %v", err)
+		}
+	}
+	return written, removes, nil
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"MyBlog/internal/article"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDirIndexesWithoutArticles 最后一篇文章撤回后，--per-dir 仍然清理遗留的索引文件
+func TestDirIndexesWithoutArticles(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	stale := filepath.Join("blogs", "Go", article.IndexFileName)
+	manual := filepath.Join("blogs", "Rust", article.IndexFileName)
+	for path, content := range map[string]string{
+		stale:  article.IndexMarker + "\n# Go\n",
+		manual: "# 手写的 README\n",
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writes, removes, err := dirIndexChanges(nil)
+	if err != nil {
+		t.Fatalf("dirIndexChanges: %v", err)
+	}
+	if len(writes) != 0 || strings.Join(removes, ",") != stale {
+		t.Fatalf("writes = %d, removes = %q, want 只删除 %s", len(writes), removes, stale)
+	}
+
+	written, removed, err := generateDirIndexes(nil)
+	if err != nil {
+		t.Fatalf("generateDirIndexes: %v", err)
+	}
+	if len(written) != 0 || len(removed) != 1 {
+		t.Errorf("written = %q, removed = %q", written, removed)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("遗留的索引文件没有删除: %v", err)
+	}
+	if _, err := os.Stat(manual); err != nil {
+		t.Errorf("手写的 README.md 被删除: %v", err)
+	}
+
+	// 清理后 --check 不再报告变化
+	writes, removes, err = dirIndexChanges(nil)
+	if err != nil || len(writes) != 0 || len(removes) != 0 {
+		t.Errorf("清理后 dirIndexChanges = %d, %q, %v", len(writes), removes, err)
+	}
+}
+
+// TestDirIndexesWithoutBlogsDir 博客目录不存在时没有需要修改的索引文件
+func TestDirIndexesWithoutBlogsDir(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	writes, removes, err := dirIndexChanges(nil)
+	if err != nil {
+		t.Fatalf("dirIndexChanges: %v", err)
+	}
+	if len(writes) != 0 || len(removes) != 0 {
+		t.Errorf("writes = %d, removes = %q", len(writes), removes)
+	}
+}
@@ -0,0 +1,410 @@
+package cmd
+
+import (
+	"MyBlog/internal/config"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	listScope   string
+	listTag     string
+	listSince   string
+	listUntil   string
+	listTitle   string
+	listSort    string
+	listReverse bool
+	listFormat  string
+)
+
+var ListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出草稿和已发布的文章",
+	Long: `列出草稿目录和博客目录中的文章，支持过滤、排序和机器可读的输出格式。
+
+过滤条件可以组合使用：
+  --tag    按标签路径前缀过滤，例如 Go 匹配 Go、Go/并发，但不匹配 Golang
+  --since  只显示该日期及之后的文章 (格式: 2006-01-02)
+  --until  只显示该日期及之前的文章 (格式: 2006-01-02)
+  --title  标题包含指定文字（不区分大小写）
+
+已发布文章使用发布时间，草稿使用创建时间。
+设置了 publish_at 的草稿显示为定时发布，并显示距离发布还有多久。`,
+	Example: `  myblog list
+  myblog list --scope drafts
+  myblog list --tag Go/设计模式 --sort title
+  myblog list --since 2025-01-01 --format json
+  myblog list --format csv > articles.csv`,
+	Args: cobra.NoArgs,
+	Run:  runListCommand,
+}
+
+func init() {
+	ListCmd.Flags().StringVarP(&listScope, "scope", "s", "all", "文章范围: drafts, published, all")
+	ListCmd.Flags().StringVarP(&listTag, "tag", "t", "", "按标签路径前缀过滤")
+	ListCmd.Flags().StringVar(&listSince, "since", "", "起始日期 (2006-01-02)")
+	ListCmd.Flags().StringVar(&listUntil, "until", "", "截止日期 (2006-01-02)")
+	ListCmd.Flags().StringVar(&listTitle, "title", "", "按标题关键字过滤")
+	ListCmd.Flags().StringVar(&listSort, "sort", "date", "排序方式: date, title")
+	ListCmd.Flags().BoolVarP(&listReverse, "reverse", "r", false, "反转排序顺序")
+	ListCmd.Flags().StringVarP(&listFormat, "format", "f", "table", "输出格式: table, json, csv")
+}
+
+// listFilter 文章过滤条件
+type listFilter struct {
+	tag   []string
+	since time.Time
+	until time.Time
+	title string
+}
+
+func runListCommand(cmd *cobra.Command, args []string) {
+	red := color.New(color.FgRed).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	filter, err := newListFilter()
+	if err != nil {
+		fmt.Printf("%s %v\n", red("错误:"), err)
+		return
+	}
+
+	articles, err := collectArticles(listScope)
+	if err != nil {
+		fmt.Printf("%s %v\n", red("错误:"), err)
+		logrus.WithError(err).Error("扫描文章失败")
+		return
+	}
+
+	var matched []GenArticleInfo
+	for _, article := range articles {
+		if filter.match(article) {
+			matched = append(matched, article)
+		}
+	}
+
+	if err := sortArticles(matched, listSort, listReverse); err != nil {
+		fmt.Printf("%s %v\n", red("错误:"), err)
+		return
+	}
+
+	switch listFormat {
+	case "json":
+		err = printArticlesJSON(matched)
+	case "csv":
+		err = printArticlesCSV(matched)
+	case "table":
+		if len(matched) == 0 {
+			fmt.Printf("%s 没有找到符合条件的文章\n", yellow("提示:"))
+			return
+		}
+		printArticlesTable(matched)
+	default:
+		err = fmt.Errorf("不支持的输出格式: %s (可选: table, json, csv)", listFormat)
+	}
+	if err != nil {
+		fmt.Printf("%s %v\n", red("错误:"), err)
+	}
+}
+
+// collectArticles 按范围收集草稿和已发布的文章
+func collectArticles(scope string) ([]GenArticleInfo, error) {
+	var articles []GenArticleInfo
+
+	switch scope {
+	case "all", "published", "drafts":
+	default:
+		return nil, fmt.Errorf("不支持的文章范围: %s (可选: drafts, published, all)", scope)
+	}
+
+	if scope == "all" || scope == "drafts" {
+		drafts, err := scanDraftArticles()
+		if err != nil {
+			return nil, fmt.Errorf("扫描草稿失败: %v", err)
+		}
+		articles = append(articles, drafts...)
+	}
+
+	if scope == "all" || scope == "published" {
+		published, err := scanPublishedArticles()
+		if err != nil {
+			return nil, fmt.Errorf("扫描文章失败: %v", err)
+		}
+		articles = append(articles, published...)
+	}
+
+	return articles, nil
+}
+
+func newListFilter() (*listFilter, error) {
+	filter := &listFilter{title: strings.ToLower(listTitle)}
+
+	for _, tag := range strings.Split(listTag, "/") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			filter.tag = append(filter.tag, tag)
+		}
+	}
+
+	if listSince != "" {
+		since, err := time.ParseInLocation("2006-01-02", listSince, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("无效的起始日期: %s (格式: 2006-01-02)", listSince)
+		}
+		filter.since = since
+	}
+	if listUntil != "" {
+		until, err := time.ParseInLocation("2006-01-02", listUntil, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("无效的截止日期: %s (格式: 2006-01-02)", listUntil)
+		}
+		// 包含截止日期当天
+		filter.until = until.AddDate(0, 0, 1)
+	}
+
+	return filter, nil
+}
+
+func (f *listFilter) match(article GenArticleInfo) bool {
+	if len(f.tag) > len(article.Tags) {
+		return false
+	}
+	for i, tag := range f.tag {
+		if article.Tags[i] != tag {
+			return false
+		}
+	}
+
+	if !f.since.IsZero() && article.Published.Before(f.since) {
+		return false
+	}
+	if !f.until.IsZero() && !article.Published.Before(f.until) {
+		return false
+	}
+
+	if f.title != "" && !strings.Contains(strings.ToLower(article.Title), f.title) {
+		return false
+	}
+
+	return true
+}
+
+// sortArticles 按日期（从新到旧）或标题排序
+func sortArticles(articles []GenArticleInfo, by string, reverse bool) error {
+	var less func(i, j int) bool
+	switch by {
+	case "date":
+		less = func(i, j int) bool { return articles[i].Published.After(articles[j].Published) }
+	case "title":
+		less = func(i, j int) bool { return articles[i].Title < articles[j].Title }
+	default:
+		return fmt.Errorf("不支持的排序方式: %s (可选: date, title)", by)
+	}
+
+	if reverse {
+		sort.SliceStable(articles, func(i, j int) bool { return less(j, i) })
+	} else {
+		sort.SliceStable(articles, less)
+	}
+	return nil
+}
+
+// articleStatus 文章状态的文字描述，定时发布的草稿附带倒计时
+func articleStatus(article GenArticleInfo) string {
+	if !article.FromDrafts {
+		return "已发布"
+	}
+	if article.PublishAt.IsZero() {
+		return "草稿"
+	}
+	return fmt.Sprintf("定时 (%s)", formatCountdown(time.Until(article.PublishAt)))
+}
+
+// formatCountdown 将剩余时间格式化为倒计时文字
+func formatCountdown(d time.Duration) string {
+	if d <= 0 {
+		return "已到期"
+	}
+
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%d天%d小时后", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%d小时%d分钟后", hours, minutes)
+	case minutes > 0:
+		return fmt.Sprintf("%d分钟后", minutes)
+	default:
+		return "不到1分钟"
+	}
+}
+
+// articleSourcePath 文章文件相对于项目根目录的路径
+func articleSourcePath(article GenArticleInfo) string {
+	root := config.GetBlogsDir()
+	if article.FromDrafts {
+		root = config.GetDraftDir()
+	}
+	return filepath.ToSlash(filepath.Join(root, article.RelativePath))
+}
+
+// listRecord 机器可读输出中的一篇文章
+type listRecord struct {
+	Title     string     `json:"title"`
+	Status    string     `json:"status"`
+	Date      time.Time  `json:"date"`
+	PublishAt *time.Time `json:"publish_at,omitempty"`
+	Tags      []string   `json:"tags"`
+	Path      string     `json:"path"`
+}
+
+func newListRecord(article GenArticleInfo) listRecord {
+	status := "published"
+	var publishAt *time.Time
+	if article.FromDrafts {
+		status = "draft"
+		if !article.PublishAt.IsZero() {
+			status = "scheduled"
+			publishAt = &article.PublishAt
+		}
+	}
+	tags := article.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return listRecord{
+		Title:     article.Title,
+		Status:    status,
+		Date:      article.Published,
+		PublishAt: publishAt,
+		Tags:      tags,
+		Path:      articleSourcePath(article),
+	}
+}
+
+func printArticlesJSON(articles []GenArticleInfo) error {
+	records := make([]listRecord, len(articles))
+	for i, article := range articles {
+		records[i] = newListRecord(article)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(records)
+}
+
+func printArticlesCSV(articles []GenArticleInfo) error {
+	writer := csv.NewWriter(os.Stdout)
+	writer.Write([]string{"title", "status", "date", "tags", "path", "publish_at"})
+	for _, article := range articles {
+		record := newListRecord(article)
+		date := ""
+		if !record.Date.IsZero() {
+			date = record.Date.Format(time.RFC3339)
+		}
+		publishAt := ""
+		if record.PublishAt != nil {
+			publishAt = record.PublishAt.Format(time.RFC3339)
+		}
+		writer.Write([]string{record.Title, record.Status, date, strings.Join(record.Tags, "/"), record.Path, publishAt})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func printArticlesTable(articles []GenArticleInfo) {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	blue := color.New(color.FgBlue).SprintFunc()
+	bold := color.New(color.Bold).SprintFunc()
+
+	headers := []string{"状态", "日期", "标题", "标签", "路径"}
+	rows := make([][]string, len(articles))
+	for i, article := range articles {
+		date := ""
+		if !article.Published.IsZero() {
+			date = article.Published.Format("2006-01-02")
+		}
+		rows[i] = []string{articleStatus(article), date, article.Title, strings.Join(article.Tags, "/"), articleSourcePath(article)}
+	}
+
+	widths := make([]int, len(headers))
+	for i, header := range headers {
+		widths[i] = displayWidth(header)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if w := displayWidth(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	// 每列使用不同的颜色，填充空格需在着色前计算
+	colorize := []func(a ...interface{}) string{nil, blue, nil, yellow, nil}
+	printRow := func(row []string, header bool) {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			padding := strings.Repeat(" ", widths[i]-displayWidth(cell))
+			switch {
+			case header:
+				cell = bold(cell)
+			case i == 0 && row[0] != "已发布":
+				cell = yellow(cell)
+			case i == 0:
+				cell = green(cell)
+			case colorize[i] != nil:
+				cell = colorize[i](cell)
+			}
+			cells[i] = cell + padding
+		}
+		fmt.Println(strings.TrimRight(strings.Join(cells, "  "), " "))
+	}
+
+	printRow(headers, true)
+	for _, row := range rows {
+		printRow(row, false)
+	}
+
+	drafts, scheduled := 0, 0
+	for _, article := range articles {
+		if article.FromDrafts {
+			drafts++
+			if !article.PublishAt.IsZero() {
+				scheduled++
+			}
+		}
+	}
+	fmt.Printf("\n共 %d 篇文章 (已发布 %d 篇，草稿 %d 篇，其中定时发布 %d 篇)\n", len(articles), len(articles)-drafts, drafts, scheduled)
+}
+
+// displayWidth 计算字符串在终端中的显示宽度，中日韩等全角字符占两列
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		switch {
+		case r < 0x1100:
+			width++
+		case unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hangul, r) ||
+			unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) ||
+			(r >= 0x3000 && r <= 0x303F) || (r >= 0xFF00 && r <= 0xFF60) || (r >= 0xFFE0 && r <= 0xFFE6):
+			width += 2
+		default:
+			width++
+		}
+	}
+	return width
+}
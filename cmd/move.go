@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"MyBlog/internal/article"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var MoveCmd = &cobra.Command{
+	Use:   "mv <article> <tag path>",
+	Short: "将文章移动到另一个标签路径",
+	Long: `将草稿或已发布的文章移动到同一根目录下的另一个标签路径（即子目录）。
+
+移动时会：
+1. 更新 Front Matter 中的 tags
+2. 将文章的资源目录 (<文章名>.assets/) 一起移动
+3. 重新计算正文中的相对链接，保证仍然指向原来的文件
+
+标签路径为空字符串时移动到根目录。`,
+	Example: `  myblog mv Go/channel.md Go/并发
+  myblog mv "Go/并发/channel.md" ""`,
+	Args: cobra.ExactArgs(2),
+	Run:  runMoveCommand,
+}
+
+func init() {
+	addCommitFlag(MoveCmd)
+}
+
+func runMoveCommand(cmd *cobra.Command, args []string) {
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	blue := color.New(color.FgBlue).SprintFunc()
+
+	repo := articleRepository()
+	articlePath, err := repo.FindAny(args[0])
+	if err != nil {
+		fmt.Printf("%s %v\n", red("错误:"), err)
+		return
+	}
+
+	tags, err := article.ParseTags(args[1])
+	if err != nil {
+		fmt.Printf("%s %v\n", red("错误:"), err)
+		return
+	}
+
+	fmt.Printf("%s 正在移动文章: %s\n", blue("信息:"), yellow(filepath.Base(articlePath)))
+
+	if !beginCommit() {
+		return
+	}
+	targetPath, err := repo.Move(articlePath, tags)
+	if err != nil {
+		fmt.Printf("%s 移动失败: %v\n", red("错误:"), err)
+		logrus.WithError(err).Error("移动文章失败")
+		return
+	}
+
+	fmt.Printf("%s 成功移动文章!\n", green("✓"))
+	fmt.Printf("  原路径: %s\n", articlePath)
+	fmt.Printf("  新路径: %s\n", green(targetPath))
+	if len(tags) > 0 {
+		fmt.Printf("  标签: %s\n", strings.Join(tags, "/"))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"original_path": articlePath,
+		"target_path":   targetPath,
+		"tags":          tags,
+	}).Info("文章移动成功")
+
+	title := extractTitleFromFile(targetPath)
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(targetPath), filepath.Ext(targetPath))
+	}
+	autoCommit("mv", []string{title})
+}
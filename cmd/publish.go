@@ -1,12 +1,13 @@
 package cmd
 
 import (
+	"MyBlog/internal/article"
 	"MyBlog/internal/config"
-	"bufio"
+	"MyBlog/internal/frontmatter"
 	"fmt"
-	"os"
+	"path"
 	"path/filepath"
-	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -16,295 +17,415 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	pubTag string
+	pubDue bool
+)
+
 var PubCmd = &cobra.Command{
-	Use:   "pub [path]",
+	Use:   "pub [path...]",
 	Short: "发布草稿文章到博客目录",
 	Long: `将草稿文章从草稿目录迁移到博客目录，并更新文章的发布时间。
 
 支持以下发布方式：
-1. 按文章路径发布：提供相对于草稿目录的路径
-2. 交互式选择发布：不提供参数时进入交互模式
-
-发布后会保持原有的目录结构，并更新文章末尾的更新时间。`,
+1. 按文章路径发布：提供相对于草稿目录的路径，可以同时提供多个
+2. 按通配符批量发布：路径中可以使用 *、? 和 [...]，** 匹配任意层目录
+3. 按标签路径批量发布：--tag 发布该标签路径下的所有草稿
+4. 定时发布：--due 发布所有 publish_at 时间已到的草稿
+5. 交互式选择发布：不提供参数时进入交互模式，可以多选
+
+发布后会保持原有的目录结构，并更新文章末尾的更新时间。
+批量发布时某篇草稿失败不会影响其他草稿，结束时会列出每篇草稿的结果。`,
 	Example: `  myblog pub "Go/设计模式/实践/go设计模式实践.md"  # 按路径发布
+  myblog pub '_draft/Go/**/*.md'                    # 按通配符批量发布
+  myblog pub --tag Go/并发                          # 发布标签路径下的所有草稿
+  myblog pub --due                                  # 发布到期的定时草稿
   myblog pub                                        # 交互式选择草稿`,
-	Args: cobra.MaximumNArgs(1),
+	Args: cobra.ArbitraryArgs,
 	Run:  runPublishCommand,
 }
 
 func init() {
+	PubCmd.Flags().StringVarP(&pubTag, "tag", "t", "", "发布指定标签路径下的所有草稿，例如 Go/并发")
+	PubCmd.Flags().BoolVar(&pubDue, "due", false, "发布所有 publish_at 时间已到的草稿")
+	addCommitFlag(PubCmd)
 	PubCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "详细输出")
 }
 
 func runPublishCommand(cmd *cobra.Command, args []string) {
 	// 设置颜色输出
-	green := color.New(color.FgGreen).SprintFunc()
 	red := color.New(color.FgRed).SprintFunc()
 	yellow := color.New(color.FgYellow).SprintFunc()
-	blue := color.New(color.FgBlue).SprintFunc()
 
-	var selectedDraft string
+	var selectedDrafts []string
+	var unresolved []publishResult
+	var err error
 
 	// 获取草稿文件
-	if len(args) > 0 {
-		// 按路径查找草稿
-		draftFile, err := findDraftByPath(args[0])
-		if err != nil {
-			fmt.Printf("%s %v\n", red("错误:"), err)
+	if pubDue {
+		if len(args) > 0 || pubTag != "" {
+			fmt.Printf("%s --due 不能与路径或 --tag 同时使用\n", red("错误:"))
 			return
 		}
-		selectedDraft = draftFile
-	} else {
-		// 交互式选择草稿
-		draftFile, err := selectDraftInteractively()
-		if err != nil {
-			fmt.Printf("%s %v\n", red("错误:"), err)
+		// 查找到期的定时草稿
+		selectedDrafts, err = findDueDrafts(time.Now())
+		if err == nil && len(selectedDrafts) == 0 {
+			fmt.Printf("%s 没有到期的定时草稿\n", yellow("提示:"))
 			return
 		}
-		selectedDraft = draftFile
+	} else if len(args) > 0 || pubTag != "" {
+		// 按路径、通配符或标签路径查找草稿
+		selectedDrafts, unresolved = findDrafts(args, pubTag)
+	} else {
+		// 交互式选择草稿
+		selectedDrafts, err = selectDraftsInteractively()
+	}
+	if err != nil {
+		fmt.Printf("%s %v\n", red("错误:"), err)
+		return
 	}
 
-	if selectedDraft == "" {
+	if len(selectedDrafts) == 0 && len(unresolved) == 0 {
 		fmt.Printf("%s 没有找到要发布的草稿\n", red("错误:"))
 		return
 	}
 
-	fmt.Printf("%s 正在发布草稿: %s\n", blue("信息:"), yellow(filepath.Base(selectedDraft)))
-
-	// 发布草稿
-	publishedPath, err := publishDraft(selectedDraft)
-	if err != nil {
-		fmt.Printf("%s 发布失败: %v\n", red("错误:"), err)
-		logrus.WithError(err).Error("发布草稿失败")
+	if !beginCommit() {
 		return
 	}
+	results := publishDrafts(selectedDrafts, unresolved)
+	autoCommit("pub", publishedTitles(results))
+}
 
-	fmt.Printf("%s 成功发布草稿!\n", green("✓"))
-	fmt.Printf("  原路径: %s\n", selectedDraft)
-	fmt.Printf("  新路径: %s\n", green(publishedPath))
-	fmt.Printf("  发布时间: %s\n", time.Now().Format("2006年01月02日 15:04"))
+// publishedTitles 发布成功的文章标题
+func publishedTitles(results []publishResult) []string {
+	var titles []string
+	for _, result := range results {
+		if result.err != nil {
+			continue
+		}
+		title := extractTitleFromFile(result.publishedPath)
+		if title == "" {
+			title = strings.TrimSuffix(filepath.Base(result.publishedPath), filepath.Ext(result.publishedPath))
+		}
+		titles = append(titles, title)
+	}
+	return titles
+}
 
-	logrus.WithFields(logrus.Fields{
-		"original_path":  selectedDraft,
-		"published_path": publishedPath,
-		"publish_time":   time.Now(),
-	}).Info("草稿发布成功")
+// publishResult 单篇草稿的发布结果
+type publishResult struct {
+	draftPath     string
+	publishedPath string
+	err           error
 }
 
-// 按路径查找草稿文件
-
-// 按路径查找草稿文件
-func findDraftByPath(inputPath string) (string, error) {
-	// 规范化路径分隔符
-	inputPath = filepath.FromSlash(inputPath)
-
-	var fullPath string
-
-	// 判断输入路径是否包含草稿目录前缀
-	draftDir := config.GetDraftDir()
-	if strings.HasPrefix(inputPath, draftDir+string(filepath.Separator)) ||
-		strings.HasPrefix(inputPath, "./"+draftDir+string(filepath.Separator)) ||
-		strings.HasPrefix(inputPath, ".\\"+draftDir+string(filepath.Separator)) {
-		// 如果包含草稿目录前缀，直接使用该路径
-		fullPath = inputPath
-		// 去掉可能的 "./" 或 ".\\" 前缀
-		if strings.HasPrefix(fullPath, "./") || strings.HasPrefix(fullPath, ".\\") {
-			fullPath = fullPath[2:]
-		}
-	} else {
-		// 如果不包含草稿目录前缀，拼接草稿目录
-		fullPath = filepath.Join(draftDir, inputPath)
+// publishDrafts 依次发布草稿，单篇失败时继续发布其余草稿，最后输出汇总
+//
+// unresolved 是没有找到草稿的路径、通配符或标签路径，作为失败的结果一起列出。
+func publishDrafts(drafts []string, unresolved []publishResult) []publishResult {
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	blue := color.New(color.FgBlue).SprintFunc()
+
+	results := make([]publishResult, 0, len(unresolved)+len(drafts))
+	for _, result := range unresolved {
+		fmt.Printf("%s %v\n", red("错误:"), result.err)
+		logrus.WithError(result.err).WithField("path", result.draftPath).Error("查找草稿失败")
+		results = append(results, result)
 	}
+	for _, draft := range drafts {
+		fmt.Printf("%s 正在发布草稿: %s\n", blue("信息:"), yellow(filepath.Base(draft)))
 
-	// 检查文件是否存在
-	if _, err := os.Stat(fullPath); err != nil {
-		if os.IsNotExist(err) {
-			return "", fmt.Errorf("草稿文件不存在: %s", inputPath)
+		publishedPath, err := articleRepository().Publish(draft)
+		results = append(results, publishResult{draftPath: draft, publishedPath: publishedPath, err: err})
+		if err != nil {
+			fmt.Printf("%s 发布失败: %v\n", red("错误:"), err)
+			logrus.WithError(err).WithField("path", draft).Error("发布草稿失败")
+			continue
 		}
-		return "", fmt.Errorf("访问文件失败: %v", err)
+
+		logrus.WithFields(logrus.Fields{
+			"original_path":  draft,
+			"published_path": publishedPath,
+			"publish_time":   time.Now(),
+		}).Info("草稿发布成功")
 	}
 
-	// 验证是否为 Markdown 文件
-	if !strings.HasSuffix(strings.ToLower(fullPath), ".md") {
-		return "", fmt.Errorf("指定的文件不是 Markdown 文件: %s", inputPath)
+	// 只发布一篇时保持原来的输出
+	if len(results) == 1 {
+		if results[0].err == nil {
+			fmt.Printf("%s 成功发布草稿!\n", green("✓"))
+			fmt.Printf("  原路径: %s\n", results[0].draftPath)
+			fmt.Printf("  新路径: %s\n", green(results[0].publishedPath))
+			fmt.Printf("  发布时间: %s\n", time.Now().Format("2006年01月02日 15:04"))
+		}
+		return results
 	}
 
-	// 验证文件确实在草稿目录中
-	absFullPath, err := filepath.Abs(fullPath)
-	if err != nil {
-		return "", fmt.Errorf("获取绝对路径失败: %v", err)
+	succeeded := 0
+	fmt.Printf("\n%s 发布结果:\n", blue("信息:"))
+	for _, result := range results {
+		relPath := draftRelPath(result.draftPath)
+		if result.err != nil {
+			fmt.Printf("  %s %s: %v\n", red("✗"), relPath, result.err)
+			continue
+		}
+		succeeded++
+		fmt.Printf("  %s %s -> %s\n", green("✓"), relPath, green(result.publishedPath))
 	}
+	fmt.Printf("\n共 %d 篇草稿，成功 %s 篇，失败 %s 篇\n",
+		len(results), green(succeeded), red(len(results)-succeeded))
+
+	return results
+}
 
-	absDraftDir, err := filepath.Abs(draftDir)
+// draftRelPath 草稿相对于草稿目录的路径，用于输出
+func draftRelPath(draftPath string) string {
+	absDraftDir, err := articleRepository().Abs(config.GetDraftDir())
 	if err != nil {
-		return "", fmt.Errorf("获取草稿目录绝对路径失败: %v", err)
+		return draftPath
 	}
-
-	if !strings.HasPrefix(absFullPath, absDraftDir+string(filepath.Separator)) {
-		return "", fmt.Errorf("指定文件不在草稿目录中: %s", inputPath)
+	relPath, err := filepath.Rel(absDraftDir, draftPath)
+	if err != nil {
+		return draftPath
 	}
-
-	return absFullPath, nil
+	return filepath.ToSlash(relPath)
 }
 
-// 交互式选择草稿
-func selectDraftInteractively() (string, error) {
-	drafts := getAllDrafts()
-
-	if len(drafts) == 0 {
-		return "", fmt.Errorf("草稿目录中没有找到任何文章")
+// findDrafts 按路径、通配符和标签路径查找草稿，结果去重并保持顺序
+//
+// 找不到草稿的路径、通配符或标签路径不会中断查找，作为失败的结果返回。
+func findDrafts(patterns []string, tagPath string) ([]string, []publishResult) {
+	var drafts []string
+	var unresolved []publishResult
+	seen := make(map[string]bool)
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			drafts = append(drafts, path)
+		}
+	}
+	fail := func(pattern string, err error) {
+		unresolved = append(unresolved, publishResult{draftPath: pattern, err: err})
 	}
 
-	options := make([]string, len(drafts))
-	for i, draft := range drafts {
-		relPath, _ := filepath.Rel(config.GetDraftDir(), draft)
-		title := extractTitleFromFile(draft)
-		if title != "" {
-			options[i] = fmt.Sprintf("%s (%s)", title, relPath)
-		} else {
-			options[i] = relPath
+	for _, pattern := range patterns {
+		if !isGlobPattern(pattern) {
+			draftFile, err := articleRepository().Find(article.Draft, pattern)
+			if err != nil {
+				fail(pattern, err)
+				continue
+			}
+			add(draftFile)
+			continue
 		}
-	}
 
-	var selectedIndex int
-	prompt := &survey.Select{
-		Message: "请选择要发布的草稿:",
-		Options: options,
-		Help:    "选择一篇草稿文章发布到博客目录",
+		matches, err := globDrafts(pattern)
+		if err != nil {
+			fail(pattern, err)
+			continue
+		}
+		if len(matches) == 0 {
+			fail(pattern, fmt.Errorf("没有匹配的草稿: %s", pattern))
+			continue
+		}
+		for _, match := range matches {
+			add(match)
+		}
 	}
 
-	if err := survey.AskOne(prompt, &selectedIndex); err != nil {
-		return "", err
+	if tagPath != "" {
+		matches, err := findDraftsByTag(tagPath)
+		if err == nil && len(matches) == 0 {
+			err = fmt.Errorf("标签路径下没有草稿: %s", tagPath)
+		}
+		if err != nil {
+			fail(tagPath, err)
+		}
+		for _, match := range matches {
+			add(match)
+		}
 	}
 
-	return drafts[selectedIndex], nil
+	return drafts, unresolved
 }
 
-// 从文件中提取标题
-func extractTitleFromFile(filePath string) string {
-	file, err := os.Open(filePath)
+// findDueDrafts 查找 publish_at 不晚于 now 的草稿，按定时发布时间排序
+func findDueDrafts(now time.Time) ([]string, error) {
+	drafts, err := scanDraftArticles()
 	if err != nil {
-		return ""
+		return nil, fmt.Errorf("扫描草稿失败: %v", err)
 	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	inFrontMatter := false
 
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+	sort.SliceStable(drafts, func(i, j int) bool {
+		return drafts[i].PublishAt.Before(drafts[j].PublishAt)
+	})
 
-		if line == "---" {
-			if !inFrontMatter {
-				inFrontMatter = true
-				continue
-			} else {
-				break
-			}
+	repo := articleRepository()
+	var due []string
+	for _, draft := range drafts {
+		if draft.PublishAt.IsZero() || draft.PublishAt.After(now) {
+			continue
+		}
+		absPath, err := repo.Abs(draft.FilePath)
+		if err != nil {
+			return nil, fmt.Errorf("获取绝对路径失败: %v", err)
 		}
+		due = append(due, absPath)
+	}
+	return due, nil
+}
+
+// findDraftsByTag 查找草稿目录中指定标签路径（即子目录）下的所有草稿
+func findDraftsByTag(tagPath string) ([]string, error) {
+	segments, err := article.ParseTags(tagPath)
+	if err != nil || len(segments) == 0 {
+		return nil, fmt.Errorf("无效的标签路径: %s", tagPath)
+	}
 
-		if inFrontMatter && strings.HasPrefix(line, "title:") {
-			titleValue := strings.TrimSpace(strings.TrimPrefix(line, "title:"))
-			return strings.Trim(titleValue, `"'`)
+	dir := filepath.Join(append([]string{config.GetDraftDir()}, segments...)...)
+	repo := articleRepository()
+	info, err := repo.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("标签路径不存在: %s", tagPath)
+	}
+
+	var drafts []string
+	for _, draft := range repo.Files(article.Draft, segments...) {
+		absPath, err := repo.Abs(draft)
+		if err != nil {
+			return nil, fmt.Errorf("获取绝对路径失败: %v", err)
 		}
+		drafts = append(drafts, absPath)
 	}
+	return drafts, nil
+}
 
-	return ""
+// isGlobPattern 判断路径中是否包含通配符
+func isGlobPattern(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
 }
 
-// 获取所有草稿文件
-func getAllDrafts() []string {
+// globDrafts 查找匹配通配符的草稿，模式可以相对于当前目录或草稿目录
+func globDrafts(pattern string) ([]string, error) {
+	pattern = strings.TrimPrefix(filepath.ToSlash(pattern), "./")
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("无效的通配符: %s", pattern)
+	}
+
+	draftDir := filepath.ToSlash(filepath.Clean(config.GetDraftDir()))
+
+	repo := articleRepository()
 	var drafts []string
+	for _, draft := range getAllDrafts() {
+		draftPath := filepath.ToSlash(draft)
+		relPath := strings.TrimPrefix(draftPath, draftDir+"/")
+		if !matchGlob(pattern, draftPath) && !matchGlob(pattern, relPath) {
+			continue
+		}
 
-	filepath.Walk(config.GetDraftDir(), func(path string, info os.FileInfo, err error) error {
+		absPath, err := repo.Abs(draft)
 		if err != nil {
-			return err
+			return nil, fmt.Errorf("获取绝对路径失败: %v", err)
 		}
+		drafts = append(drafts, absPath)
+	}
+	return drafts, nil
+}
 
-		if !info.IsDir() && strings.HasSuffix(strings.ToLower(path), ".md") {
-			drafts = append(drafts, path)
-		}
+// matchGlob 按路径段匹配通配符，** 匹配零个或多个目录
+func matchGlob(pattern, name string) bool {
+	return matchGlobSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
 
-		return nil
-	})
+func matchGlobSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// 连续的 ** 等价于一个
+			for len(pattern) > 0 && pattern[0] == "**" {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 0 {
+				return true
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchGlobSegments(pattern, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
 
-	return drafts
+		if len(name) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(pattern[0], name[0]); !matched {
+			return false
+		}
+		pattern = pattern[1:]
+		name = name[1:]
+	}
+	return len(name) == 0
 }
 
-// 发布草稿到博客目录
-func publishDraft(draftPath string) (string, error) {
-	// 获取草稿目录的绝对路径
-	absDraftDir, err := filepath.Abs(config.GetDraftDir())
-	if err != nil {
-		return "", fmt.Errorf("获取草稿目录绝对路径失败: %v", err)
-	}
+// 交互式选择草稿，可以多选
+func selectDraftsInteractively() ([]string, error) {
+	drafts := getAllDrafts()
 
-	// 确保输入路径是绝对路径
-	absDraftPath, err := filepath.Abs(draftPath)
-	if err != nil {
-		return "", fmt.Errorf("获取草稿文件绝对路径失败: %v", err)
+	if len(drafts) == 0 {
+		return nil, fmt.Errorf("草稿目录中没有找到任何文章")
 	}
 
-	// 计算相对于草稿目录的路径
-	relPath, err := filepath.Rel(absDraftDir, absDraftPath)
-	if err != nil {
-		return "", fmt.Errorf("计算相对路径失败: %v", err)
+	options := make([]string, len(drafts))
+	for i, draft := range drafts {
+		relPath, _ := filepath.Rel(config.GetDraftDir(), draft)
+		title := extractTitleFromFile(draft)
+		if title != "" {
+			options[i] = fmt.Sprintf("%s (%s)", title, relPath)
+		} else {
+			options[i] = relPath
+		}
 	}
 
-	// 构建目标路径（保持相同的目录结构）
-	targetPath := filepath.Join(config.GetBlogsDir(), relPath)
+	var selectedIndexes []int
+	prompt := &survey.MultiSelect{
+		Message: "请选择要发布的草稿:",
+		Options: options,
+		Help:    "空格键选择或取消，回车确认，可以同时发布多篇草稿",
+	}
 
-	// 检查目标文件是否已存在
-	if _, err := os.Stat(targetPath); err == nil {
-		return "", fmt.Errorf("目标文件已存在: %s", targetPath)
+	if err := survey.AskOne(prompt, &selectedIndexes, survey.WithValidator(survey.Required)); err != nil {
+		return nil, err
 	}
 
-	// 确保目标目录存在（只在需要时创建）
-	targetDir := filepath.Dir(targetPath)
-	if _, err := os.Stat(targetDir); os.IsNotExist(err) {
-		if err := os.MkdirAll(targetDir, 0755); err != nil {
-			return "", fmt.Errorf("创建目标目录失败: %v", err)
+	repo := articleRepository()
+	selected := make([]string, len(selectedIndexes))
+	for i, index := range selectedIndexes {
+		absPath, err := repo.Abs(drafts[index])
+		if err != nil {
+			return nil, fmt.Errorf("获取绝对路径失败: %v", err)
 		}
+		selected[i] = absPath
 	}
+	return selected, nil
+}
 
-	// 读取原文件内容
-	content, err := os.ReadFile(absDraftPath)
+// 从文件中提取标题
+func extractTitleFromFile(filePath string) string {
+	content, err := articleRepository().ReadFile(filePath)
 	if err != nil {
-		return "", fmt.Errorf("读取草稿文件失败: %v", err)
-	}
-
-	// 更新文章末尾的时间戳
-	updatedContent := updateTimestamp(string(content))
-
-	// 写入目标文件
-	if err := os.WriteFile(targetPath, []byte(updatedContent), 0644); err != nil {
-		return "", fmt.Errorf("写入目标文件失败: %v", err)
+		return ""
 	}
-
-	// 删除原草稿文件
-	if err := os.Remove(absDraftPath); err != nil {
-		// 如果删除失败，尝试删除已创建的目标文件
-		os.Remove(targetPath)
-		return "", fmt.Errorf("删除原草稿文件失败: %v", err)
+	doc, err := frontmatter.Parse(content)
+	if err != nil {
+		return ""
 	}
-
-	return targetPath, nil
+	return doc.Metadata().Title
 }
 
-// 更新文章末尾的时间戳
-func updateTimestamp(content string) string {
-	now := time.Now()
-	newTimestamp := fmt.Sprintf("> 更新时间: %s", now.Format("2006年01月02日 15:04"))
-
-	// 使用正则表达式匹配并替换时间戳
-	timestampRegex := regexp.MustCompile(`> 更新时间: .+`)
-
-	if timestampRegex.MatchString(content) {
-		// 如果找到现有时间戳，替换它
-		return timestampRegex.ReplaceAllString(content, newTimestamp)
-	} else {
-		// 如果没有找到时间戳，在文章末尾添加
-		content = strings.TrimRight(content, "\n")
-		return content + "\n\n---\n\n" + newTimestamp + "\n"
-	}
+// 获取所有草稿文件
+func getAllDrafts() []string {
+	return articleRepository().Files(article.Draft)
 }
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestFindDraftsUnresolved 找不到的路径作为失败结果返回，不影响其他草稿
+func TestFindDraftsUnresolved(t *testing.T) {
+	t.Chdir(t.TempDir())
+	draft := filepath.Join("_draft", "Go", "a.md")
+	if err := os.MkdirAll(filepath.Dir(draft), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(draft, []byte("---\ntitle: a\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	drafts, unresolved := findDrafts([]string{"_draft/nope.md", "_draft/Go/a.md", "Rust/*.md"}, "Rust")
+	if len(drafts) != 1 || filepath.Base(drafts[0]) != "a.md" {
+		t.Errorf("drafts = %q", drafts)
+	}
+
+	var patterns []string
+	for _, result := range unresolved {
+		if result.err == nil {
+			t.Errorf("%s 没有错误", result.draftPath)
+		}
+		patterns = append(patterns, result.draftPath)
+	}
+	if len(patterns) != 3 || patterns[0] != "_draft/nope.md" || patterns[1] != "Rust/*.md" || patterns[2] != "Rust" {
+		t.Errorf("unresolved = %q", patterns)
+	}
+}
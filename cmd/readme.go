@@ -0,0 +1,382 @@
+package cmd
+
+import (
+	"MyBlog/internal/anchor"
+	"MyBlog/internal/config"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// readmeTemplateName README 模板在模板目录中的文件名
+const readmeTemplateName = "readme.md.tmpl"
+
+// readmeData README 模板中可以使用的数据
+type readmeData struct {
+	TagGroups []TagGroup
+	// Tree 标签树，超过最大层数的标签归入上层标签
+	Tree []*TagNode
+	// Layout 文章分类的布局 (tree/details/flat)
+	Layout        string
+	TotalArticles int
+	TotalTags     int
+	// BlogsDir 博客目录，使用 "/" 分隔
+	BlogsDir string
+	// UpdatedAt 最近一篇文章的更新时间，内容不变时 README 也不变
+	UpdatedAt time.Time
+	// GeneratedAt 生成时间，使用它会导致每次生成的 README 都不同
+	GeneratedAt time.Time
+	// Config 全部配置，包括配置文件中自定义的配置项
+	Config map[string]interface{}
+}
+
+// readmeFuncs README 模板中可以使用的函数
+var readmeFuncs = template.FuncMap{
+	// date 按 Go 的时间格式格式化时间，例如 {{ date "2006-01-02" .Published }}
+	"date": func(layout string, t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(layout)
+	},
+	// count 标签分类中的文章总数
+	"count": func(groups []TagGroup) int {
+		total := 0
+		for _, group := range groups {
+			total += len(group.Articles)
+		}
+		return total
+	},
+	// articleLink 文章相对于仓库根目录的链接
+	"articleLink": func(article GenArticleInfo) string {
+		return path.Join(filepath.ToSlash(config.GetBlogsDir()), article.RelativePath)
+	},
+	"join": func(sep string, s []string) string {
+		return strings.Join(s, sep)
+	},
+	// heading 标签树中第 depth 层标签的标题标记，顶层为 ###，最多 ######
+	"heading": func(depth int) string {
+		if depth > 4 {
+			depth = 4
+		}
+		return strings.Repeat("#", depth+2)
+	},
+	// indent 标签树中第 depth 层标签的列表缩进
+	"indent": func(depth int) string {
+		return strings.Repeat("  ", depth-1)
+	},
+	// tagHeading 标签树中标签的标题，包括子标签在内的文章总数，例如 Go (23)
+	"tagHeading": func(node *TagNode) string {
+		return fmt.Sprintf("%s (%d)", node.Name, node.Count)
+	},
+}
+
+// readmeAnchorProfile 生成标题锚点的平台规则
+func readmeAnchorProfile() anchor.Profile {
+	profile, err := anchor.ParseProfile(config.GetReadmeAnchor())
+	if err != nil {
+		logrus.WithError(err).Warn("锚点规则无效，使用 github")
+		return anchor.GitHub
+	}
+	return profile
+}
+
+// readmeTemplatePath README 模板文件的路径
+func readmeTemplatePath() string {
+	return filepath.Join(config.GetTemplateDir(), readmeTemplateName)
+}
+
+// loadReadmeTemplate 读取模板目录中的 README 模板，不存在时使用内置模板
+//
+// 模板中的 anchor 函数（标题对应的页内锚点）由 links 记录，README 生成后再替换为实际的锚点。
+func loadReadmeTemplate(links *anchorLinks) (*template.Template, error) {
+	text := defaultReadmeTemplate
+	content, err := articleRepository().ReadFile(readmeTemplatePath())
+	if err == nil {
+		text = string(content)
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("读取README模板失败: %v", err)
+	}
+
+	tmpl, err := template.New(readmeTemplateName).
+		Funcs(readmeFuncs).
+		Funcs(template.FuncMap{"anchor": links.placeholder}).
+		Option("missingkey=zero").
+		Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("解析README模板失败: %v", err)
+	}
+	return tmpl, nil
+}
+
+// readmeLayout 文章分类的布局，--layout 优先于 readme.layout 配置
+func readmeLayout() string {
+	layout := genLayout
+	if layout == "" {
+		layout = config.GetReadmeLayout()
+	}
+	for _, name := range config.ReadmeLayouts {
+		if layout == name {
+			return layout
+		}
+	}
+	logrus.WithField("layout", layout).Warn("README布局无效，使用 flat")
+	return "flat"
+}
+
+// readmeMaxDepth 标签树的最大层数，--max-depth 优先于 readme.max_depth 配置
+func readmeMaxDepth() int {
+	if genMaxDepth > 0 {
+		return genMaxDepth
+	}
+	return config.GetReadmeMaxDepth()
+}
+
+// newReadmeData 准备 README 模板使用的数据
+func newReadmeData(tagGroups []TagGroup) readmeData {
+	data := readmeData{
+		TagGroups:   tagGroups,
+		Tree:        buildTagTree(tagGroups, readmeMaxDepth()),
+		Layout:      readmeLayout(),
+		TotalTags:   len(tagGroups),
+		BlogsDir:    filepath.ToSlash(config.GetBlogsDir()),
+		GeneratedAt: time.Now(),
+		Config:      config.Settings(),
+	}
+	for _, group := range tagGroups {
+		data.TotalArticles += len(group.Articles)
+		for _, article := range group.Articles {
+			if article.Updated.After(data.UpdatedAt) {
+				data.UpdatedAt = article.Updated
+			}
+		}
+	}
+	return data
+}
+
+// renderReadme 生成 README 内容
+//
+// existing 为当前的 README.md，其中有标记区域时只更新标记区域，否则使用 README 模板重新生成全部内容。
+func renderReadme(existing []byte, tagGroups []TagGroup) ([]byte, error) {
+	// 锚点在整个 README 生成后才能确定
+	links := &anchorLinks{}
+	tmpl, err := loadReadmeTemplate(links)
+	if err != nil {
+		return nil, err
+	}
+	data := newReadmeData(tagGroups)
+
+	var content []byte
+	if readmeMarkerRegex.Match(existing) {
+		content, err = updateReadmeRegions(existing, tmpl, data)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("渲染README模板失败: %v", err)
+		}
+		content = buf.Bytes()
+	}
+	return links.resolve(content, readmeAnchorProfile()), nil
+}
+
+// defaultReadmeRegion 没有写名称的标记区域使用的模板
+const defaultReadmeRegion = "index"
+
+// readmeMarkerRegex 匹配 README 中的区域标记，例如 <!-- myblog:start nav --> 和 <!-- myblog:end nav -->
+var readmeMarkerRegex = regexp.MustCompile(`<!--\s*myblog:(start|end)(?:\s+([\w-]+))?\s*-->`)
+
+// updateReadmeRegions 使用 README 模板中同名的模板替换每个标记区域的内容，区域外的内容保持不变
+func updateReadmeRegions(existing []byte, tmpl *template.Template, data readmeData) ([]byte, error) {
+	var buf bytes.Buffer
+	last := 0
+	region := ""
+	for _, m := range readmeMarkerRegex.FindAllSubmatchIndex(existing, -1) {
+		kind := string(existing[m[2]:m[3]])
+		name := defaultReadmeRegion
+		if m[4] >= 0 {
+			name = string(existing[m[4]:m[5]])
+		}
+
+		if kind == "start" {
+			if region != "" {
+				return nil, fmt.Errorf("README中的区域 %s 没有结束标记", region)
+			}
+			if tmpl.Lookup(name) == nil {
+				return nil, fmt.Errorf("README中的区域 %s 没有对应的模板", name)
+			}
+			buf.Write(existing[last:m[1]])
+			region = name
+			last = m[1]
+			continue
+		}
+
+		if region == "" {
+			return nil, fmt.Errorf("README中的区域 %s 缺少开始标记", name)
+		}
+		if name != region && m[4] >= 0 {
+			return nil, fmt.Errorf("README中的区域 %s 的结束标记与开始标记 %s 不一致", name, region)
+		}
+
+		var content bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&content, region, data); err != nil {
+			return nil, fmt.Errorf("渲染README区域 %s 失败: %v", region, err)
+		}
+		buf.WriteString("\n")
+		buf.WriteString(strings.Trim(content.String(), "\n"))
+		buf.WriteString("\n")
+		buf.Write(existing[m[0]:m[1]])
+		region = ""
+		last = m[1]
+	}
+	if region != "" {
+		return nil, fmt.Errorf("README中的区域 %s 没有结束标记", region)
+	}
+
+	buf.Write(existing[last:])
+	return buf.Bytes(), nil
+}
+
+// defaultReadmeTemplate 内置的 README 模板，gen --export-template 可以导出到模板目录中修改
+//
+// nav、stats、articles 和 index 同时用于 README.md 中同名的标记区域。
+const defaultReadmeTemplate = `{{ define "nav" -}}
+### 标签分类快速跳转
+{{ if eq .Layout "flat" }}
+{{- range .TagGroups }}
+- [{{ .TagPath }}](#{{ anchor .TagPath }}) ({{ len .Articles }}篇)
+{{- end }}
+{{- else if eq .Layout "details" }}
+{{- range .Tree }}{{ template "navText" . }}{{ end }}
+{{- else }}
+{{- range .Tree }}{{ template "navLink" . }}{{ end }}
+{{- end }}
+{{- end }}
+
+{{- define "navLink" }}
+{{ indent .Depth }}- [{{ .Name }}](#{{ anchor (tagHeading .) }}) ({{ .Count }}篇)
+{{- range .Children }}{{ template "navLink" . }}{{ end }}
+{{- end }}
+
+{{- define "navText" }}
+{{ indent .Depth }}- {{ .Name }} ({{ .Count }}篇)
+{{- range .Children }}{{ template "navText" . }}{{ end }}
+{{- end }}
+
+{{- define "stats" -}}
+**📊 统计信息**: 共 {{ .TotalTags }} 个标签分类，{{ .TotalArticles }} 篇文章
+{{- end }}
+
+{{- define "articles" -}}
+{{ if eq .Layout "flat" -}}
+{{ range $i, $group := .TagGroups -}}
+{{ if $i }}
+
+{{ end -}}
+### {{ $group.TagPath }}
+{{ range $group.Articles }}
+{{ template "articleItem" . }}
+{{- end }}
+{{- end }}
+{{- else -}}
+{{ range $i, $node := .Tree -}}
+{{ if $i }}
+
+{{ end -}}
+{{ if eq $.Layout "details" }}{{ template "detailsNode" $node }}{{ else }}{{ template "treeNode" $node }}{{ end }}
+{{- end }}
+{{- end }}
+{{- end }}
+
+{{- define "articleItem" -}}
+- [{{ .Title }}]({{ articleLink . }}) - *{{ date "2006-01-02" .Published }}*
+{{- end }}
+
+{{- define "treeNode" -}}
+{{ heading .Depth }} {{ tagHeading . }}
+{{- if .Articles }}
+{{ range .Articles }}
+{{ template "articleItem" . }}
+{{- end }}
+{{- end }}
+{{- range .Children }}
+
+{{ template "treeNode" . }}
+{{- end }}
+{{- end }}
+
+{{- define "detailsNode" -}}
+<details>
+<summary>{{ tagHeading . }}</summary>
+{{- if .Articles }}
+{{ range .Articles }}
+{{ template "articleItem" . }}
+{{- end }}
+{{- end }}
+{{- range .Children }}
+
+{{ template "detailsNode" . }}
+{{- end }}
+
+</details>
+{{- end }}
+
+{{- define "index" -}}
+## 📚 文章导航
+
+{{ template "nav" . }}
+
+{{ template "stats" . }}
+
+---
+
+## 📖 文章分类
+
+{{ template "articles" . }}
+{{- end -}}
+
+# MyBlog - 简易静态博客系统
+
+MyBlog 是一个简易的静态博客系统，类似于 Hugo，专为命令行使用而设计。它借助 GitHub 对 Markdown 文档的完美支持，让 Markdown 文档成为你的界面。
+
+## 特性
+
+- 🚀 **简单易用**: 基于命令行的简洁界面
+- 📝 **Markdown 支持**: 完美支持 Markdown 格式
+- 🏷️ **标签和分类**: 支持文章标签和分类管理
+- 🎨 **交互式命令**: 美观的交互式命令行界面
+- 🌈 **彩色输出**: 支持彩色终端输出
+- 📁 **自动组织**: 自动创建和组织文件结构
+
+## 快速开始
+
+### 构建项目
+
+` + "```bash\ngo build -o myblog .\n```" + `
+
+### 使用帮助
+
+详细使用说明请参考：[使用帮助文档](help.md)
+
+{{ template "index" . }}
+
+---
+
+## 可用命令
+
+` + "- `draft` - 创建草稿文章\n" +
+	"- `new` - 创建正式文章\n" +
+	"- `pub` - 发布草稿到正式文章\n" +
+	"- `gen` - 生成README.md文档\n" + `{{ if not .UpdatedAt.IsZero }}
+*文章最后更新: {{ date "2006-01-02 15:04:05" .UpdatedAt }}*
+{{ end }}`
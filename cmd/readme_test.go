@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// testTagGroups README 测试使用的文章分组
+func testTagGroups() []TagGroup {
+	updated := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	return []TagGroup{
+		{TagPath: "Go", Articles: []GenArticleInfo{{Title: "基础", Tags: []string{"Go"}, RelativePath: "Go/基础.md", Updated: updated}}},
+		{TagPath: "Go/并发", Articles: []GenArticleInfo{{Title: "channel", Tags: []string{"Go", "并发"}, RelativePath: "Go/并发/channel.md", Updated: updated}}},
+	}
+}
+
+// TestDefaultReadmeLayout 没有配置 readme.layout 时保持原来每个标签路径一个标题的布局
+func TestDefaultReadmeLayout(t *testing.T) {
+	useRepositoryFS(t, afero.NewMemMapFs(), false)
+	if layout := readmeLayout(); layout != "flat" {
+		t.Fatalf("readmeLayout() = %s, want flat", layout)
+	}
+
+	content, err := renderReadme(nil, testTagGroups())
+	if err != nil {
+		t.Fatalf("renderReadme: %v", err)
+	}
+	if !strings.Contains(string(content), "### Go/并发\n") {
+		t.Errorf("默认布局不是 flat:\n%s", content)
+	}
+}
+
+// TestReadmeFooter 没有文章时不输出最后更新时间
+func TestReadmeFooter(t *testing.T) {
+	useRepositoryFS(t, afero.NewMemMapFs(), false)
+
+	content, err := renderReadme(nil, nil)
+	if err != nil {
+		t.Fatalf("renderReadme: %v", err)
+	}
+	if strings.Contains(string(content), "文章最后更新") {
+		t.Errorf("没有文章时输出了最后更新时间:\n%s", content)
+	}
+	if !strings.HasSuffix(string(content), "生成README.md文档\n") {
+		t.Errorf("README 结尾:\n%q", content[len(content)-40:])
+	}
+
+	content, err = renderReadme(nil, testTagGroups())
+	if err != nil {
+		t.Fatalf("renderReadme: %v", err)
+	}
+	if !strings.HasSuffix(string(content), "\n\n*文章最后更新: 2026-10-01 09:00:00*\n") {
+		t.Errorf("README 结尾:\n%q", content[len(content)-80:])
+	}
+}
+
+// TestUpdateReadmeRegions 只替换标记区域中的内容，标记不完整时返回错误
+func TestUpdateReadmeRegions(t *testing.T) {
+	tmpl := template.Must(template.New("readme").Parse(
+		`{{ define "nav" }}导航 {{ .TotalArticles }}{{ end }}{{ define "stats" }}统计{{ end }}{{ define "index" }}` + "\n索引\n" + `{{ end }}`))
+	data := readmeData{TotalArticles: 2}
+
+	tests := []struct {
+		name     string
+		existing string
+		want     string
+		err      string
+	}{
+		{
+			name:     "区域外的内容保持不变",
+			existing: "# 手写标题\n\n<!-- myblog:start nav -->\n旧导航\n<!-- myblog:end nav -->\n\n手写的结尾 <!-- 注释 -->\n",
+			want:     "# 手写标题\n\n<!-- myblog:start nav -->\n导航 2\n<!-- myblog:end nav -->\n\n手写的结尾 <!-- 注释 -->\n",
+		},
+		{
+			name:     "省略名称的区域使用 index",
+			existing: "前\n<!-- myblog:start -->旧<!-- myblog:end -->后",
+			want:     "前\n<!-- myblog:start -->\n索引\n<!-- myblog:end -->后",
+		},
+		{
+			name:     "省略名称的结束标记结束当前区域",
+			existing: "<!-- myblog:start stats --><!-- myblog:end -->",
+			want:     "<!-- myblog:start stats -->\n统计\n<!-- myblog:end -->",
+		},
+		{
+			name:     "同名的区域都会更新",
+			existing: "<!-- myblog:start nav -->旧<!-- myblog:end nav -->\n中间\n<!--myblog:start   nav--><!--myblog:end nav-->",
+			want:     "<!-- myblog:start nav -->\n导航 2\n<!-- myblog:end nav -->\n中间\n<!--myblog:start   nav-->\n导航 2\n<!--myblog:end nav-->",
+		},
+		{
+			name:     "缺少结束标记",
+			existing: "<!-- myblog:start nav -->\n旧导航\n",
+			err:      "区域 nav 没有结束标记",
+		},
+		{
+			name:     "区域结束前开始新的区域",
+			existing: "<!-- myblog:start nav --><!-- myblog:start stats --><!-- myblog:end stats -->",
+			err:      "区域 nav 没有结束标记",
+		},
+		{
+			name:     "结束标记的名称不一致",
+			existing: "<!-- myblog:start nav -->旧<!-- myblog:end stats -->",
+			err:      "结束标记与开始标记 nav 不一致",
+		},
+		{
+			name:     "缺少开始标记",
+			existing: "旧<!-- myblog:end nav -->",
+			err:      "区域 nav 缺少开始标记",
+		},
+		{
+			name:     "没有对应的模板",
+			existing: "<!-- myblog:start footer --><!-- myblog:end footer -->",
+			err:      "区域 footer 没有对应的模板",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := updateReadmeRegions([]byte(tt.existing), tmpl, data)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("err = %v, want %s", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("updateReadmeRegions: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"MyBlog/internal/article"
+	"MyBlog/internal/config"
+	"MyBlog/internal/frontmatter"
+	"MyBlog/internal/slug"
+
+	"github.com/sirupsen/logrus"
+)
+
+// newRepository 创建文章仓库，测试中可以替换为使用内存文件系统或覆盖层的仓库
+var newRepository = article.NewRepository
+
+// articleRepository 按当前配置创建文章仓库，命令读写项目中的文件都通过它进行
+func articleRepository() *article.Repository {
+	repo := newRepository(config.GetDraftDir(), config.GetBlogsDir())
+	repo.TemplateDir = config.GetTemplateDir()
+	repo.Author = config.GetAuthor()
+	repo.Config = config.Settings()
+
+	if format, err := frontmatter.ParseFormat(config.GetFrontMatterFormat()); err != nil {
+		logrus.WithError(err).Warn("Front Matter格式无效，使用 yaml")
+	} else {
+		repo.Format = format
+	}
+
+	if strategy, err := slug.ParseStrategy(config.GetSlugStrategy()); err != nil {
+		logrus.WithError(err).Warn("slug策略无效，使用 keep")
+	} else {
+		repo.Slug = strategy
+	}
+
+	return repo
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	scheduleInterval time.Duration
+	scheduleOnce     bool
+	schedulePerDir   bool
+)
+
+var ScheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "定时发布到期的草稿",
+	Long: `持续运行并定期检查草稿目录，发布所有 publish_at 时间已到的草稿，
+有草稿被发布后会重新生成 README.md，使用 --per-dir 时同时更新每个标签目录的索引文件。
+
+在草稿的 Front Matter 中设置定时发布时间：
+  publish_at: 2026-11-01T09:00:00+08:00
+
+只想检查一次时可以使用 --once，或者使用 myblog pub --due。`,
+	Example: `  myblog schedule
+  myblog schedule --interval 10m
+  myblog schedule --once
+  myblog schedule --per-dir`,
+	Args: cobra.NoArgs,
+	Run:  runScheduleCommand,
+}
+
+func init() {
+	ScheduleCmd.Flags().DurationVarP(&scheduleInterval, "interval", "i", time.Minute, "检查间隔")
+	ScheduleCmd.Flags().BoolVar(&scheduleOnce, "once", false, "只检查一次后退出")
+	ScheduleCmd.Flags().BoolVar(&schedulePerDir, "per-dir", false, "发布后同时更新每个标签目录中的索引文件 README.md")
+	addCommitFlag(ScheduleCmd)
+}
+
+func runScheduleCommand(cmd *cobra.Command, args []string) {
+	// 设置颜色输出
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	blue := color.New(color.FgBlue).SprintFunc()
+
+	if scheduleInterval <= 0 {
+		fmt.Printf("%s 检查间隔必须大于0\n", red("错误:"))
+		return
+	}
+
+	if scheduleOnce {
+		publishDueDrafts()
+		return
+	}
+
+	// Ctrl+C 时停止检查
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	fmt.Printf("%s 定时发布已启动，每 %s 检查一次\n", green("✓"), scheduleInterval)
+	fmt.Println("  按 Ctrl+C 停止")
+
+	ticker := time.NewTicker(scheduleInterval)
+	defer ticker.Stop()
+
+	publishDueDrafts()
+	for {
+		select {
+		case <-ticker.C:
+			publishDueDrafts()
+		case <-signals:
+			fmt.Printf("\n%s 定时发布已停止\n", blue("信息:"))
+			return
+		}
+	}
+}
+
+// publishDueDrafts 发布所有到期的草稿，有草稿发布成功时与 gen 一样重新生成 README.md 和目录索引
+//
+// 重新生成失败时仍然提交已经发布的文章。
+func publishDueDrafts() {
+	red := color.New(color.FgRed).SprintFunc()
+	blue := color.New(color.FgBlue).SprintFunc()
+
+	drafts, err := findDueDrafts(time.Now())
+	if err != nil {
+		fmt.Printf("%s %v\n", red("错误:"), err)
+		logrus.WithError(err).Error("查找到期草稿失败")
+		return
+	}
+	if len(drafts) == 0 {
+		logrus.Debug("没有到期的定时草稿")
+		return
+	}
+
+	fmt.Printf("%s %s 发现 %d 篇到期的草稿\n", blue("信息:"), time.Now().Format("2006-01-02 15:04:05"), len(drafts))
+
+	if !beginCommit() {
+		return
+	}
+	results := publishDrafts(drafts, nil)
+	titles := publishedTitles(results)
+	published := len(titles)
+	if published == 0 {
+		return
+	}
+
+	// 错误已经输出，已发布的文章和已更新的索引照常提交
+	result, _ := regenerateIndexes(schedulePerDir)
+
+	autoCommit("pub", titles)
+
+	logrus.WithFields(logrus.Fields{
+		"published":      published,
+		"articles_count": result.articles,
+	}).Info("定时发布完成")
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"MyBlog/internal/article"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+// dueDraft 定时发布时间已到的草稿
+const dueDraft = "---\ntitle: 到期\ntags: [Go]\npublish_at: 2020-01-01T09:00:00+08:00\n---\n\n正文\n"
+
+// TestPublishDueDraftsPerDir --per-dir 时定时发布与 gen 一样更新目录索引
+func TestPublishDueDraftsPerDir(t *testing.T) {
+	t.Chdir(t.TempDir())
+	fs := afero.NewMemMapFs()
+	useRepositoryFS(t, fs, false)
+	if err := afero.WriteFile(fs, filepath.Join("_draft", "Go", "到期.md"), []byte(dueDraft), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	previous := schedulePerDir
+	schedulePerDir = true
+	t.Cleanup(func() { schedulePerDir = previous })
+	publishDueDrafts()
+
+	if exists, _ := afero.Exists(fs, filepath.Join("blogs", "Go", "到期.md")); !exists {
+		t.Fatal("到期的草稿没有发布")
+	}
+	if exists, _ := afero.Exists(fs, "README.md"); !exists {
+		t.Error("没有生成 README.md")
+	}
+	if exists, _ := afero.Exists(fs, filepath.Join("blogs", "Go", article.IndexFileName)); !exists {
+		t.Error("没有更新目录索引")
+	}
+}
+
+// TestPublishDueDraftsCommitsOnGenError 重新生成 README.md 失败时仍然提交已发布的文章
+func TestPublishDueDraftsCommitsOnGenError(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("找不到 git 命令")
+	}
+	dir := t.TempDir()
+	t.Chdir(dir)
+	git := func(args ...string) string {
+		t.Helper()
+		output, err := exec.Command("git", args...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+		return string(output)
+	}
+	git("init", "--quiet")
+	git("config", "user.name", "myblog")
+	git("config", "user.email", "myblog@example.com")
+
+	for path, content := range map[string]string{
+		filepath.Join("_draft", "Go", "到期.md"):       dueDraft,
+		filepath.Join("templates", "readme.md.tmpl"): "{{ .没有闭合",
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	previous := gitCommit
+	gitCommit = true
+	t.Cleanup(func() { gitCommit = previous })
+	publishDueDrafts()
+
+	if _, err := os.Stat("README.md"); !os.IsNotExist(err) {
+		t.Fatalf("README 模板无效时不应生成 README.md: %v", err)
+	}
+	files := git("-c", "core.quotepath=off", "show", "--name-only", "--format=", "HEAD")
+	if !strings.Contains(files, "blogs/Go/到期.md") {
+		t.Errorf("没有提交已发布的文章:\n%s", files)
+	}
+}
@@ -0,0 +1,391 @@
+package cmd
+
+import (
+	"MyBlog/internal/config"
+	"MyBlog/internal/fsys"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	servePort    int
+	serveBind    string
+	serveDrafts  bool
+	serveVerbose bool
+)
+
+var ServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "启动本地预览服务器，文章修改后自动刷新浏览器",
+	Long: `在本地渲染站点并通过HTTP服务器提供预览。
+
+服务器会监听博客目录（使用 --drafts 时还包括草稿目录）中的文件变化，
+Markdown文件被修改后会自动重新渲染受影响的页面，并通知已打开的浏览器刷新。
+
+草稿文章的页面地址为 drafts/<文章相对路径>/。`,
+	Example: `  myblog serve
+  myblog serve --drafts
+  myblog serve --port 8080`,
+	Args: cobra.NoArgs,
+	Run:  runServeCommand,
+}
+
+func init() {
+	ServeCmd.Flags().IntVarP(&servePort, "port", "p", 1313, "监听端口")
+	ServeCmd.Flags().StringVar(&serveBind, "bind", "127.0.0.1", "监听地址")
+	ServeCmd.Flags().BoolVarP(&serveDrafts, "drafts", "D", false, "同时预览草稿目录中的文章")
+	ServeCmd.Flags().BoolVarP(&serveVerbose, "verbose", "v", false, "详细输出")
+}
+
+func runServeCommand(cmd *cobra.Command, args []string) {
+	// 设置颜色输出
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	blue := color.New(color.FgBlue).SprintFunc()
+
+	// 预览需要把站点渲染到临时目录
+	if fsys.DryRun() {
+		fmt.Printf("%s serve 不支持 --dry-run\n", red("错误:"))
+		return
+	}
+
+	if serveVerbose {
+		logrus.SetLevel(logrus.DebugLevel)
+	}
+
+	outDir, err := os.MkdirTemp("", "myblog-serve-")
+	if err != nil {
+		fmt.Printf("%s 创建临时目录失败: %v\n", red("错误:"), err)
+		return
+	}
+	defer os.RemoveAll(outDir)
+
+	server := &previewServer{
+		builder: newSiteBuilder(outDir),
+		reload:  newReloadHub(),
+	}
+	server.builder.includeDrafts = serveDrafts
+	server.builder.liveReload = true
+
+	fmt.Printf("%s 正在渲染站点...\n", blue("信息:"))
+	if err := server.rebuildAll(); err != nil {
+		fmt.Printf("%s 构建站点失败: %v\n", red("错误:"), err)
+		return
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		fmt.Printf("%s 创建文件监听失败: %v\n", red("错误:"), err)
+		return
+	}
+	defer watcher.Close()
+
+	for _, dir := range server.watchDirs() {
+		if err := watchRecursively(watcher, dir); err != nil {
+			fmt.Printf("%s 监听目录失败 %s: %v\n", red("错误:"), dir, err)
+			return
+		}
+	}
+	go server.watch(watcher)
+
+	mux := http.NewServeMux()
+	mux.Handle("/__livereload", server.reload)
+	mux.Handle("/", http.FileServer(http.Dir(outDir)))
+
+	addr := fmt.Sprintf("%s:%d", serveBind, servePort)
+	httpServer := &http.Server{Addr: addr, Handler: mux}
+
+	// Ctrl+C 时关闭服务器，确保临时目录被清理
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		fmt.Printf("\n%s 正在停止预览服务器...\n", blue("信息:"))
+		server.reload.close()
+		httpServer.Close()
+	}()
+
+	fmt.Printf("%s 预览服务器已启动: %s\n", green("✓"), green("http://"+addr+"/"))
+	if serveDrafts {
+		fmt.Printf("  草稿预览: %s\n", green("已开启"))
+	}
+	fmt.Println("  按 Ctrl+C 停止")
+
+	if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		fmt.Printf("%s 预览服务器异常退出: %v\n", red("错误:"), err)
+		logrus.WithError(err).Error("预览服务器异常退出")
+	}
+}
+
+// previewServer 预览服务器的状态
+type previewServer struct {
+	mu       sync.Mutex
+	builder  *siteBuilder
+	reload   *reloadHub
+	articles []GenArticleInfo
+}
+
+// watchDirs 需要监听的文章目录
+func (s *previewServer) watchDirs() []string {
+	dirs := []string{config.GetBlogsDir()}
+	if s.builder.includeDrafts {
+		dirs = append(dirs, config.GetDraftDir())
+	}
+	return dirs
+}
+
+// scan 扫描预览所需的全部文章
+func (s *previewServer) scan() ([]GenArticleInfo, error) {
+	articles, err := scanPublishedArticles()
+	if err != nil {
+		return nil, err
+	}
+	if s.builder.includeDrafts {
+		drafts, err := scanDraftArticles()
+		if err != nil {
+			return nil, err
+		}
+		articles = append(articles, drafts...)
+	}
+	return articles, nil
+}
+
+// rebuildAll 清空临时目录并重新渲染整个站点
+func (s *previewServer) rebuildAll() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	articles, err := s.scan()
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(s.builder.outDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := os.RemoveAll(filepath.Join(s.builder.outDir, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	if _, err := s.builder.buildAll(articles); err != nil {
+		return err
+	}
+	s.articles = articles
+	return nil
+}
+
+// rebuildChanged 只重新渲染发生变化的文章，以及依赖文章列表的首页和标签页
+//
+// 有文章被删除，或者修改后的文章页面地址、标签、是否渲染发生变化时，旧的文章页和
+// 可能变空的标签页都需要清理，此时改为完整重建。
+func (s *previewServer) rebuildChanged(changed map[string]bool) error {
+	s.mu.Lock()
+
+	articles, err := s.scan()
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+
+	current := make(map[string]GenArticleInfo, len(articles))
+	for _, article := range articles {
+		absPath, _ := filepath.Abs(article.FilePath)
+		current[absPath] = article
+	}
+	for _, previous := range s.articles {
+		absPath, _ := filepath.Abs(previous.FilePath)
+		article, ok := current[absPath]
+		if !ok || (changed[absPath] && s.movesPages(previous, article)) {
+			s.mu.Unlock()
+			return s.rebuildAll()
+		}
+	}
+	defer s.mu.Unlock()
+
+	for _, article := range articles {
+		absPath, _ := filepath.Abs(article.FilePath)
+		if changed[absPath] && s.builder.renders(article) {
+			if err := s.builder.buildArticle(article); err != nil {
+				return err
+			}
+			logrus.WithField("path", article.FilePath).Debug("重新渲染文章")
+		}
+	}
+
+	if _, err := s.builder.buildIndexes(articles); err != nil {
+		return err
+	}
+	s.articles = articles
+	return nil
+}
+
+// movesPages 文章修改后旧的页面是否会遗留在站点中：页面地址或标签变化，或者是否渲染、
+// 是否出现在列表中发生变化（标签页可能变空）
+func (s *previewServer) movesPages(previous, article GenArticleInfo) bool {
+	return articlePagePath(previous) != articlePagePath(article) ||
+		!slices.Equal(previous.Tags, article.Tags) ||
+		s.builder.renders(previous) != s.builder.renders(article) ||
+		previous.Unlisted != article.Unlisted
+}
+
+// watch 处理文件变化事件，短时间内的多次变化合并为一次重建
+func (s *previewServer) watch(watcher *fsnotify.Watcher) {
+	yellow := color.New(color.FgYellow).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+
+	const debounce = 200 * time.Millisecond
+
+	changed := make(map[string]bool)
+	staticChanged := false
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Has(fsnotify.Chmod) {
+				continue
+			}
+
+			// 新建的子目录也需要监听
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					watchRecursively(watcher, event.Name)
+					staticChanged = true
+				}
+			}
+
+			if strings.HasSuffix(strings.ToLower(event.Name), ".md") {
+				absPath, _ := filepath.Abs(event.Name)
+				changed[absPath] = true
+			} else {
+				staticChanged = true
+			}
+			timer.Reset(debounce)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			logrus.WithError(err).Warn("文件监听出错")
+
+		case <-timer.C:
+			var err error
+			if staticChanged {
+				err = s.rebuildAll()
+			} else {
+				err = s.rebuildChanged(changed)
+			}
+			if err != nil {
+				fmt.Printf("%s 重新渲染失败: %v\n", red("错误:"), err)
+			} else {
+				fmt.Printf("%s 检测到 %d 个文件变化，已重新渲染 (%s)\n", yellow("更新:"), len(changed), time.Now().Format("15:04:05"))
+				s.reload.broadcast()
+			}
+			changed = make(map[string]bool)
+			staticChanged = false
+		}
+	}
+}
+
+// watchRecursively 监听目录及其所有子目录
+func watchRecursively(watcher *fsnotify.Watcher, dir string) error {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return watcher.Add(path)
+		}
+		return nil
+	})
+}
+
+// reloadHub 通过 Server-Sent Events 通知浏览器刷新页面
+type reloadHub struct {
+	mu      sync.Mutex
+	clients map[chan struct{}]bool
+	done    chan struct{}
+}
+
+func newReloadHub() *reloadHub {
+	return &reloadHub{
+		clients: make(map[chan struct{}]bool),
+		done:    make(chan struct{}),
+	}
+}
+
+func (h *reloadHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	flusher.Flush()
+
+	client := make(chan struct{}, 1)
+	h.mu.Lock()
+	h.clients[client] = true
+	h.mu.Unlock()
+
+	defer func() {
+		h.mu.Lock()
+		delete(h.clients, client)
+		h.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-client:
+			fmt.Fprint(w, "data: reload\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		case <-h.done:
+			return
+		}
+	}
+}
+
+// broadcast 通知所有已连接的浏览器刷新
+func (h *reloadHub) broadcast() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for client := range h.clients {
+		select {
+		case client <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// close 断开所有浏览器连接
+func (h *reloadHub) close() {
+	close(h.done)
+}
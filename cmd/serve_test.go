@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestRebuildChangedMovesPages 修改文章的标签或 slug 后，旧的文章页和标签页不会遗留
+func TestRebuildChangedMovesPages(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	articlePath := filepath.Join("blogs", "hello.md")
+	writeArticle := func(content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(articlePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(articlePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeArticle("---\ntitle: 你好\ntags: [Go]\npublished: 2025-01-02T00:00:00Z\n---\n\n正文\n")
+
+	outDir := t.TempDir()
+	s := &previewServer{builder: newSiteBuilder(outDir)}
+	if err := s.rebuildAll(); err != nil {
+		t.Fatalf("rebuildAll: %v", err)
+	}
+	exists := func(rel string) bool {
+		_, err := os.Stat(filepath.Join(outDir, filepath.FromSlash(rel), "index.html"))
+		return err == nil
+	}
+	if !exists("posts/hello") || !exists("tags/Go") {
+		t.Fatal("第一次构建缺少页面")
+	}
+
+	absPath, _ := filepath.Abs(articlePath)
+	changed := map[string]bool{absPath: true}
+
+	// 只修改正文，页面地址不变
+	writeArticle("---\ntitle: 你好\ntags: [Go]\npublished: 2025-01-02T00:00:00Z\n---\n\n新的正文\n")
+	if err := s.rebuildChanged(changed); err != nil {
+		t.Fatalf("rebuildChanged: %v", err)
+	}
+	if !exists("posts/hello") || !exists("tags/Go") {
+		t.Error("修改正文后缺少页面")
+	}
+
+	// 修改标签和 slug
+	writeArticle("---\ntitle: 你好\ntags: [Rust]\nslug: hi\npublished: 2025-01-02T00:00:00Z\n---\n\n正文\n")
+	if err := s.rebuildChanged(changed); err != nil {
+		t.Fatalf("rebuildChanged: %v", err)
+	}
+	if !exists("posts/hi") || !exists("tags/Rust") {
+		t.Error("缺少新的文章页或标签页")
+	}
+	if exists("posts/hello") {
+		t.Error("旧的文章页仍然存在")
+	}
+	if exists("tags/Go") {
+		t.Error("旧的标签页仍然存在")
+	}
+}
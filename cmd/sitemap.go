@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"MyBlog/internal/config"
+	"MyBlog/internal/fsys"
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sitemapOutDir string
+)
+
+var SitemapCmd = &cobra.Command{
+	Use:   "sitemap",
+	Short: "生成sitemap.xml和robots.txt",
+	Long: `根据已发布的文章生成站点地图 sitemap.xml 和 robots.txt。
+
+站点地图包含首页、所有标签分类页和文章页，文章的 <lastmod> 取自 Front Matter
+中的 updated（或 lastmod）字段，没有时使用发布时间。
+Front Matter 中标记为 draft: true 或 unlisted: true 的文章不会出现在站点地图中。
+
+需要先设置站点地址：
+  myblog config set site.base_url https://example.com/blog/`,
+	Example: `  myblog sitemap
+  myblog sitemap --out public/`,
+	Args: cobra.NoArgs,
+	Run:  runSitemapCommand,
+}
+
+func init() {
+	SitemapCmd.Flags().StringVarP(&sitemapOutDir, "out", "o", "public", "输出目录")
+}
+
+func runSitemapCommand(cmd *cobra.Command, args []string) {
+	// 设置颜色输出
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	blue := color.New(color.FgBlue).SprintFunc()
+
+	if config.GetBaseURL() == "" {
+		fmt.Printf("%s 未设置站点地址，请先运行: myblog config set site.base_url <地址>\n", red("错误:"))
+		return
+	}
+
+	fmt.Printf("%s 开始扫描已发布的文章...\n", blue("信息:"))
+
+	articles, err := scanPublishedArticles()
+	if err != nil {
+		fmt.Printf("%s 扫描文章失败: %v\n", red("错误:"), err)
+		logrus.WithError(err).Error("扫描文章失败")
+		return
+	}
+
+	urls, err := writeSitemap(articles, sitemapOutDir)
+	if err != nil {
+		fmt.Printf("%s 生成站点地图失败: %v\n", red("错误:"), err)
+		logrus.WithError(err).Error("生成站点地图失败")
+		return
+	}
+
+	fmt.Printf("%s 成功生成站点地图!\n", green("✓"))
+	fmt.Printf("  页面数量: %s\n", yellow(fmt.Sprintf("%d", urls)))
+	fmt.Printf("  文件路径: %s\n", green(filepath.Join(sitemapOutDir, "sitemap.xml")))
+	fmt.Printf("  文件路径: %s\n", green(filepath.Join(sitemapOutDir, "robots.txt")))
+
+	logrus.WithFields(logrus.Fields{
+		"urls": urls,
+		"out":  sitemapOutDir,
+	}).Info("站点地图生成成功")
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// writeSitemap 写入 sitemap.xml 和 robots.txt，返回站点地图中的地址数量
+func writeSitemap(articles []GenArticleInfo, outDir string) (int, error) {
+	baseURL := config.GetBaseURL()
+
+	var public []GenArticleInfo
+	for _, article := range articles {
+		if isPublicArticle(article) {
+			public = append(public, article)
+		}
+	}
+
+	urlSet := sitemapURLSet{}
+	var latest time.Time
+	for _, article := range public {
+		if article.Updated.After(latest) {
+			latest = article.Updated
+		}
+	}
+	urlSet.URLs = append(urlSet.URLs, sitemapURL{Loc: baseURL, LastMod: sitemapDate(latest)})
+
+	for _, group := range groupArticlesByTags(public) {
+		var groupLatest time.Time
+		for _, article := range group.Articles {
+			if article.Updated.After(groupLatest) {
+				groupLatest = article.Updated
+			}
+		}
+		urlSet.URLs = append(urlSet.URLs, sitemapURL{
+			Loc:     baseURL + escapeURLPath(tagPagePath(group.TagPath)),
+			LastMod: sitemapDate(groupLatest),
+		})
+	}
+
+	for _, article := range public {
+		urlSet.URLs = append(urlSet.URLs, sitemapURL{
+			Loc:     articleURL(article),
+			LastMod: sitemapDate(article.Updated),
+		})
+	}
+
+	content, err := xml.MarshalIndent(urlSet, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+
+	if err := fsys.MkdirAll(outDir, 0755); err != nil {
+		return 0, fmt.Errorf("创建目录失败: %v", err)
+	}
+	if err := fsys.WriteFile(filepath.Join(outDir, "sitemap.xml"), append([]byte(xml.Header), content...), 0644); err != nil {
+		return 0, fmt.Errorf("写入sitemap.xml失败: %v", err)
+	}
+
+	robots := fmt.Sprintf("User-agent: *\nAllow: /\n\nSitemap: %ssitemap.xml\n", baseURL)
+	if err := fsys.WriteFile(filepath.Join(outDir, "robots.txt"), []byte(robots), 0644); err != nil {
+		return 0, fmt.Errorf("写入robots.txt失败: %v", err)
+	}
+
+	return len(urlSet.URLs), nil
+}
+
+// sitemapDate 格式化 <lastmod>，零值时省略
+func sitemapDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
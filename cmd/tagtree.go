@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+)
+
+// TagNode 标签树中的一个标签，例如 Go/并发 是 Go 的子节点
+type TagNode struct {
+	// Name 标签名称，例如 并发
+	Name string
+	// TagPath 完整的标签路径，例如 Go/并发
+	TagPath string
+	// Depth 层数，顶层标签为 1
+	Depth int
+	// Articles 直接位于该标签下的文章，超过最大层数的文章归入最深一层的标签
+	Articles []GenArticleInfo
+	Children []*TagNode
+	// Count 该标签及所有子标签中的文章总数
+	Count int
+}
+
+// buildTagTree 将按标签路径分组的文章整理为标签树，maxDepth 为 0 时不限制层数
+func buildTagTree(tagGroups []TagGroup, maxDepth int) []*TagNode {
+	root := &TagNode{}
+	nodes := make(map[string]*TagNode)
+
+	for _, group := range tagGroups {
+		tags := strings.Split(group.TagPath, "/")
+		if maxDepth > 0 && len(tags) > maxDepth {
+			tags = tags[:maxDepth]
+		}
+
+		parent := root
+		for i, tag := range tags {
+			tagPath := strings.Join(tags[:i+1], "/")
+			node, ok := nodes[tagPath]
+			if !ok {
+				node = &TagNode{Name: tag, TagPath: tagPath, Depth: i + 1}
+				nodes[tagPath] = node
+				parent.Children = append(parent.Children, node)
+			}
+			node.Count += len(group.Articles)
+			parent = node
+		}
+		parent.Articles = append(parent.Articles, group.Articles...)
+	}
+
+	for _, node := range nodes {
+		// 归入上层标签的文章重新按发布时间排序（最新的在前）
+		sort.SliceStable(node.Articles, func(i, j int) bool {
+			return node.Articles[i].Published.After(node.Articles[j].Published)
+		})
+		sort.Slice(node.Children, func(i, j int) bool {
+			return node.Children[i].Name < node.Children[j].Name
+		})
+	}
+	sort.Slice(root.Children, func(i, j int) bool {
+		return root.Children[i].Name < root.Children[j].Name
+	})
+
+	return root.Children
+}
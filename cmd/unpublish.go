@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"MyBlog/internal/article"
+	"MyBlog/internal/config"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	unpubStrip bool
+)
+
+var UnpubCmd = &cobra.Command{
+	Use:   "unpub [path]",
+	Short: "撤回已发布的文章到草稿目录",
+	Long: `将已发布的文章从博客目录移回草稿目录，是 pub 命令的逆操作。
+
+支持以下撤回方式：
+1. 按文章路径撤回：提供相对于博客目录的路径
+2. 交互式选择撤回：不提供参数时进入交互模式
+
+撤回后会在草稿目录中保持原有的目录结构。Front Matter 中的 published 字段
+默认改名为 last_published 保留原发布时间，使用 --strip 则直接删除。
+草稿目录中已存在同名文件时不会覆盖。`,
+	Example: `  myblog unpub "Go/设计模式/实践/go设计模式实践.md"  # 按路径撤回
+  myblog unpub                                        # 交互式选择文章
+  myblog unpub --strip "Go/并发/channel.md"           # 同时删除发布时间`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runUnpublishCommand,
+}
+
+func init() {
+	UnpubCmd.Flags().BoolVar(&unpubStrip, "strip", false, "删除发布时间，而不是保存为 last_published")
+	addCommitFlag(UnpubCmd)
+	UnpubCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "详细输出")
+}
+
+func runUnpublishCommand(cmd *cobra.Command, args []string) {
+	// 设置颜色输出
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	blue := color.New(color.FgBlue).SprintFunc()
+
+	var selectedArticle string
+
+	// 获取文章文件
+	if len(args) > 0 {
+		// 按路径查找文章
+		articleFile, err := findPublishedByPath(args[0])
+		if err != nil {
+			fmt.Printf("%s %v\n", red("错误:"), err)
+			return
+		}
+		selectedArticle = articleFile
+	} else {
+		// 交互式选择文章
+		articleFile, err := selectPublishedInteractively()
+		if err != nil {
+			fmt.Printf("%s %v\n", red("错误:"), err)
+			return
+		}
+		selectedArticle = articleFile
+	}
+
+	if selectedArticle == "" {
+		fmt.Printf("%s 没有找到要撤回的文章\n", red("错误:"))
+		return
+	}
+
+	fmt.Printf("%s 正在撤回文章: %s\n", blue("信息:"), yellow(filepath.Base(selectedArticle)))
+
+	// 撤回文章
+	if !beginCommit() {
+		return
+	}
+	draftPath, err := articleRepository().Unpublish(selectedArticle, unpubStrip)
+	if err != nil {
+		fmt.Printf("%s 撤回失败: %v\n", red("错误:"), err)
+		logrus.WithError(err).Error("撤回文章失败")
+		return
+	}
+
+	fmt.Printf("%s 成功撤回文章!\n", green("✓"))
+	fmt.Printf("  原路径: %s\n", selectedArticle)
+	fmt.Printf("  新路径: %s\n", green(draftPath))
+
+	logrus.WithFields(logrus.Fields{
+		"original_path": selectedArticle,
+		"draft_path":    draftPath,
+		"unpub_time":    time.Now(),
+	}).Info("文章撤回成功")
+
+	title := extractTitleFromFile(draftPath)
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(draftPath), filepath.Ext(draftPath))
+	}
+	autoCommit("unpub", []string{title})
+}
+
+// 按路径查找已发布的文章
+func findPublishedByPath(inputPath string) (string, error) {
+	return articleRepository().Find(article.Published, inputPath)
+}
+
+// 交互式选择已发布的文章
+func selectPublishedInteractively() (string, error) {
+	articles := getAllPublished()
+
+	if len(articles) == 0 {
+		return "", fmt.Errorf("博客目录中没有找到任何文章")
+	}
+
+	options := make([]string, len(articles))
+	for i, articlePath := range articles {
+		relPath, _ := filepath.Rel(config.GetBlogsDir(), articlePath)
+		title := extractTitleFromFile(articlePath)
+		if title != "" {
+			options[i] = fmt.Sprintf("%s (%s)", title, relPath)
+		} else {
+			options[i] = relPath
+		}
+	}
+
+	var selectedIndex int
+	prompt := &survey.Select{
+		Message: "请选择要撤回的文章:",
+		Options: options,
+		Help:    "选择一篇已发布的文章移回草稿目录",
+	}
+
+	if err := survey.AskOne(prompt, &selectedIndex); err != nil {
+		return "", err
+	}
+
+	return articles[selectedIndex], nil
+}
+
+// 获取所有已发布的文章文件
+func getAllPublished() []string {
+	return articleRepository().Files(article.Published)
+}
@@ -0,0 +1,104 @@
+// Package anchor 按代码托管平台的规则生成 Markdown 标题的页内锚点
+package anchor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Profile 锚点的生成规则
+type Profile string
+
+const (
+	// GitHub 转为小写，删除除字母、数字、"_"、"-" 和空格以外的字符，每个空格替换为 "-"
+	GitHub Profile = "github"
+	// GitLab 转为小写，删除除字母、十进制数字、"_"、"-" 和空格以外的字符，
+	// 空格替换为 "-" 后连续的 "-" 合并为一个
+	GitLab Profile = "gitlab"
+)
+
+// Profiles 所有可用的规则
+//
+// Gitee 没有公开锚点的生成规则，无法保证生成的锚点与 Gitee 页面一致，因此不提供。
+var Profiles = []string{string(GitHub), string(GitLab)}
+
+// ParseProfile 解析规则名称，为空时使用 GitHub
+func ParseProfile(name string) (Profile, error) {
+	switch Profile(strings.ToLower(name)) {
+	case "", GitHub:
+		return GitHub, nil
+	case GitLab:
+		return GitLab, nil
+	}
+	return "", fmt.Errorf("不支持的锚点规则: %s (可选: %s)", name, strings.Join(Profiles, ", "))
+}
+
+// Make 按规则生成标题的锚点，不处理重复的标题
+func Make(heading string, profile Profile) string {
+	heading = strings.ToLower(heading)
+	if profile == GitLab {
+		heading = strings.TrimSpace(heading)
+	}
+
+	var b strings.Builder
+	for _, r := range heading {
+		switch {
+		case r == ' ':
+			b.WriteRune('-')
+		case r == '-', unicode.Is(unicode.Pc, r), unicode.IsLetter(r), unicode.IsMark(r):
+			b.WriteRune(r)
+		case profile == GitLab && unicode.Is(unicode.Nd, r):
+			b.WriteRune(r)
+		case profile != GitLab && unicode.IsNumber(r):
+			b.WriteRune(r)
+		}
+	}
+
+	s := b.String()
+	if profile == GitLab {
+		for strings.Contains(s, "--") {
+			s = strings.ReplaceAll(s, "--", "-")
+		}
+	}
+	return s
+}
+
+// Generator 依次为文档中的标题生成锚点，重复的标题依次添加 -1、-2 等后缀
+type Generator struct {
+	profile Profile
+	used    map[string]int
+}
+
+// NewGenerator 创建锚点生成器
+func NewGenerator(profile Profile) *Generator {
+	return &Generator{profile: profile, used: make(map[string]int)}
+}
+
+// Anchor 生成下一个标题的锚点
+func (g *Generator) Anchor(heading string) string {
+	base := Make(heading, g.profile)
+
+	// GitLab 只按标题本身计数，加上后缀后可能与其他标题的锚点相同
+	if g.profile == GitLab {
+		n := g.used[base]
+		g.used[base]++
+		if n == 0 {
+			return base
+		}
+		return base + "-" + strconv.Itoa(n)
+	}
+
+	// GitHub 加上后缀后如果与已有的锚点相同（例如已经有标题 "Go-1"），继续增加编号
+	anchor := base
+	for {
+		if _, ok := g.used[anchor]; !ok {
+			break
+		}
+		g.used[base]++
+		anchor = base + "-" + strconv.Itoa(g.used[base])
+	}
+	g.used[anchor] = 0
+	return anchor
+}
@@ -0,0 +1,135 @@
+package anchor
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// 期望值按 GitHub（github-slugger）和 GitLab（Banzai TableOfContentsFilter）生成锚点的算法整理，
+// 还没有对照实际渲染的页面；testdata/headings.md 包含同样的标题，用于在两个平台上渲染后核对
+var goldenHeadings = []struct {
+	heading string
+	github  string
+	gitlab  string
+}{
+	{"Hello World", "hello-world", "hello-world"},
+	{"Hello, World! (v1.2)", "hello-world-v12", "hello-world-v12"},
+	{"C++ & Go: 入门?", "c--go-入门", "c-go-入门"},
+	{"Go/并发", "go并发", "go并发"},
+	{"Go (23)", "go-23", "go-23"},
+	{"snake_case 与 kebab-case", "snake_case-与-kebab-case", "snake_case-与-kebab-case"},
+	{"全角标点（括号）：冒号，逗号。句号！", "全角标点括号冒号逗号句号", "全角标点括号冒号逗号句号"},
+	{"Ｆｕｌｌ Ｗｉｄｔｈ", "ｆｕｌｌ-ｗｉｄｔｈ", "ｆｕｌｌ-ｗｉｄｔｈ"},
+	{"设计模式 实践", "设计模式-实践", "设计模式-实践"},
+	{"📚 文章导航", "-文章导航", "-文章导航"},
+	{"🚀 快速开始 🎉", "-快速开始-", "-快速开始-"},
+	{"A  B", "a--b", "a-b"},
+	{"A -- B", "a----b", "a-b"},
+	{"Version 2.0", "version-20", "version-20"},
+}
+
+func TestMake(t *testing.T) {
+	for _, tc := range goldenHeadings {
+		if got := Make(tc.heading, GitHub); got != tc.github {
+			t.Errorf("Make(%q, github) = %q, want %q", tc.heading, got, tc.github)
+		}
+		if got := Make(tc.heading, GitLab); got != tc.gitlab {
+			t.Errorf("Make(%q, gitlab) = %q, want %q", tc.heading, got, tc.gitlab)
+		}
+	}
+}
+
+// TestHeadingsFixture testdata/headings.md 与期望值中的标题保持一致
+func TestHeadingsFixture(t *testing.T) {
+	content, err := os.ReadFile("testdata/headings.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var headings []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if heading, ok := strings.CutPrefix(line, "## "); ok {
+			headings = append(headings, heading)
+		}
+	}
+	if len(headings) < len(goldenHeadings) {
+		t.Fatalf("testdata/headings.md 只有 %d 个标题", len(headings))
+	}
+	for i, tc := range goldenHeadings {
+		if headings[i] != tc.heading {
+			t.Errorf("第 %d 个标题 = %q, want %q", i+1, headings[i], tc.heading)
+		}
+	}
+}
+
+func TestGeneratorDuplicates(t *testing.T) {
+	tests := []struct {
+		name     string
+		profile  Profile
+		headings []string
+		want     []string
+	}{
+		{
+			name:     "github",
+			profile:  GitHub,
+			headings: []string{"Go", "Go", "Go"},
+			want:     []string{"go", "go-1", "go-2"},
+		},
+		{
+			name:     "github 后缀与已有标题冲突",
+			profile:  GitHub,
+			headings: []string{"Go", "Go-1", "Go"},
+			want:     []string{"go", "go-1", "go-2"},
+		},
+		{
+			name:     "github 已有标题与生成的后缀冲突",
+			profile:  GitHub,
+			headings: []string{"Go", "Go", "Go-1"},
+			want:     []string{"go", "go-1", "go-1-1"},
+		},
+		{
+			name:     "github 中文标题",
+			profile:  GitHub,
+			headings: []string{"实践 (1)", "实践 (1)", "实践"},
+			want:     []string{"实践-1", "实践-1-1", "实践"},
+		},
+		{
+			name:     "gitlab",
+			profile:  GitLab,
+			headings: []string{"Go", "Go", "Go"},
+			want:     []string{"go", "go-1", "go-2"},
+		},
+		{
+			// GitLab 不检查加上后缀后的锚点是否与其他标题重复
+			name:     "gitlab 已有标题与生成的后缀冲突",
+			profile:  GitLab,
+			headings: []string{"Go", "Go", "Go-1"},
+			want:     []string{"go", "go-1", "go-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGenerator(tt.profile)
+			for i, heading := range tt.headings {
+				if got := g.Anchor(heading); got != tt.want[i] {
+					t.Errorf("第 %d 个标题 %q: got %q, want %q", i+1, heading, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseProfile(t *testing.T) {
+	for name, want := range map[string]Profile{"": GitHub, "GitHub": GitHub, "gitlab": GitLab} {
+		got, err := ParseProfile(name)
+		if err != nil || got != want {
+			t.Errorf("ParseProfile(%q) = %q, %v, want %q", name, got, err, want)
+		}
+	}
+	for _, name := range []string{"gitee", "bitbucket"} {
+		if _, err := ParseProfile(name); err == nil {
+			t.Errorf("ParseProfile(%q) 应返回错误", name)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Package article 管理草稿目录和博客目录中的 Markdown 文章。
+//
+// Repository 提供创建、查找、列出、发布、撤回和移动文章的操作。标签即目录结构：
+// 标签为 Go/并发 的文章位于根目录的 Go/并发/ 子目录中。所有修改文件的操作都
+// 通过 fsys 进行，因此同样支持预演模式，移动文件时使用事务日志。
+package article
+
+import (
+	"MyBlog/internal/frontmatter"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// Status 文章所在的根目录
+type Status string
+
+const (
+	// Draft 位于草稿目录
+	Draft Status = "draft"
+	// Published 位于博客目录
+	Published Status = "published"
+)
+
+// kind 用于错误提示的名称
+func (s Status) kind() string {
+	if s == Draft {
+		return "草稿"
+	}
+	return "博客"
+}
+
+// Article 一篇文章
+type Article struct {
+	frontmatter.Metadata
+
+	// Path 文件路径，由根目录和 RelPath 拼接而成
+	Path string
+	// RelPath 相对于根目录的路径，使用 "/" 分隔
+	RelPath string
+	// Status 文章所在的根目录
+	Status Status
+	// Body 正文内容（Front Matter 之后的全部内容）
+	Body string
+}
+
+// Name 不含扩展名的文件名
+func (a *Article) Name() string {
+	name := path.Base(filepath.ToSlash(a.Path))
+	return strings.TrimSuffix(name, path.Ext(name))
+}
+
+// DirTags 文章所在目录对应的标签路径
+func (a *Article) DirTags() []string {
+	dir := path.Dir(a.RelPath)
+	if dir == "." {
+		return nil
+	}
+	return strings.Split(dir, "/")
+}
+
+// ParseTags 将 "Go/并发" 形式的标签路径拆分为标签，路径为空时没有标签
+//
+// 开头和结尾的 "/" 会被忽略，中间的空白部分以及 "."、".." 会返回错误。
+func ParseTags(tagPath string) ([]string, error) {
+	tagPath = strings.Trim(strings.TrimSpace(filepath.ToSlash(tagPath)), "/")
+	if tagPath == "" {
+		return nil, nil
+	}
+
+	tags := strings.Split(tagPath, "/")
+	for i, tag := range tags {
+		tags[i] = strings.TrimSpace(tag)
+	}
+	if err := ValidateTags(tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// ValidateTags 检查标签能否作为目录名，标签不能为空、"." 或 ".."，也不能包含路径分隔符
+func ValidateTags(tags []string) error {
+	for _, tag := range tags {
+		if tag == "" || tag == "." || tag == ".." || strings.ContainsAny(tag, `/\`) {
+			return fmt.Errorf("无效的标签: %q", tag)
+		}
+	}
+	return nil
+}
+
+// Load 从文件系统中读取并解析文章文件，Status 和 RelPath 由调用方设置
+func Load(fs afero.Fs, filePath string) (*Article, error) {
+	content, err := afero.ReadFile(fs, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %v", err)
+	}
+	doc, err := frontmatter.Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	return &Article{
+		Metadata: doc.Metadata(),
+		Path:     filePath,
+		Body:     doc.Body,
+	}, nil
+}
@@ -0,0 +1,53 @@
+package article
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"", nil},
+		{"  ", nil},
+		{"Go", []string{"Go"}},
+		{"Go/并发", []string{"Go", "并发"}},
+		{"/Go/并发/", []string{"Go", "并发"}},
+		{" Go / 设计模式 ", []string{"Go", "设计模式"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseTags(tt.input)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseTags(%q) = %q, %v, want %q", tt.input, got, err, tt.want)
+		}
+	}
+
+	for _, input := range []string{"..", "../../escaped", "Go/../..", "Go//并发", "Go/./并发", "Go/ /并发"} {
+		if tags, err := ParseTags(input); err == nil {
+			t.Errorf("ParseTags(%q) = %q, want error", input, tags)
+		}
+	}
+}
+
+func TestMoveRejectsEscapingTags(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	repo := NewRepositoryFS(fs, "_draft", "blogs")
+	articlePath := filepath.Join("blogs", "Go", "second.md")
+	if err := afero.WriteFile(fs, articlePath, []byte("---\ntitle: second\n---\n正文\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tags := range [][]string{{"..", "..", "escaped"}, {"Go", ".."}, {""}} {
+		if target, err := repo.Move(articlePath, tags); err == nil {
+			t.Errorf("Move(%q) = %s, want error", tags, target)
+		}
+	}
+	if exists, _ := afero.Exists(fs, articlePath); !exists {
+		t.Errorf("文章不应被移动: %s", articlePath)
+	}
+}
@@ -0,0 +1,93 @@
+package article
+
+import (
+	"MyBlog/internal/journal"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// AssetsSuffix 文章资源目录的后缀，文章 a.md 的图片等资源放在同目录的 a.assets/ 中
+const AssetsSuffix = ".assets"
+
+// AssetsDirName 文章资源目录的名称
+func AssetsDirName(articlePath string) string {
+	name := filepath.Base(articlePath)
+	return strings.TrimSuffix(name, filepath.Ext(name)) + AssetsSuffix
+}
+
+// AssetsDir 文章资源目录的路径
+func AssetsDir(articlePath string) string {
+	return filepath.Join(filepath.Dir(articlePath), AssetsDirName(articlePath))
+}
+
+// IsAssetsDir 判断目录是否为文章的资源目录
+func IsAssetsDir(name string) bool {
+	return strings.HasSuffix(name, AssetsSuffix)
+}
+
+// moveWithAssets 将文章写入 target 并删除 source，文章的资源目录一起移动
+//
+// 文章和所有资源文件在同一个事务中移动，中途退出或出错时不会出现文章已经移走、
+// 资源分散在两个目录中的情况。
+func (r *Repository) moveWithAssets(source, target string, content []byte) error {
+	srcAssets := AssetsDir(source)
+	dstAssets := AssetsDir(target)
+	w := r.writer()
+
+	moves := []journal.FileMove{{Source: source, Target: target, Content: content, Perm: 0644}}
+	var dirs []string
+	if info, err := r.FS.Stat(srcAssets); err == nil && info.IsDir() {
+		if _, err := r.FS.Stat(dstAssets); err == nil {
+			return fmt.Errorf("目标资源目录已存在: %s", dstAssets)
+		}
+
+		err := afero.Walk(r.FS, srcAssets, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			relPath, err := filepath.Rel(srcAssets, path)
+			if err != nil {
+				return err
+			}
+			targetPath := filepath.Join(dstAssets, relPath)
+			if info.IsDir() {
+				dirs = append(dirs, targetPath)
+				return nil
+			}
+
+			data, err := afero.ReadFile(r.FS, path)
+			if err != nil {
+				return err
+			}
+			moves = append(moves, journal.FileMove{Source: path, Target: targetPath, Content: data, Perm: info.Mode().Perm()})
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("读取资源目录失败: %v", err)
+		}
+	}
+
+	// 先创建目标资源目录，失败时删除
+	for _, dir := range dirs {
+		if err := w.MkdirAll(dir, 0755); err != nil {
+			w.RemoveAll(dstAssets)
+			return fmt.Errorf("创建资源目录失败: %v", err)
+		}
+	}
+	if err := w.MoveAll(moves); err != nil {
+		if len(dirs) > 0 {
+			w.RemoveAll(dstAssets)
+		}
+		return err
+	}
+	if len(dirs) == 0 {
+		return nil
+	}
+
+	// 文件都已移走，删除剩下的空目录
+	return w.RemoveAll(srcAssets)
+}
@@ -0,0 +1,55 @@
+package article
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// timestampLayout 文章末尾时间戳的时间格式
+const timestampLayout = "2006年01月02日 15:04"
+
+// timestampRegex 匹配文章末尾的时间戳
+var timestampRegex = regexp.MustCompile(`> 更新时间: .+`)
+
+// timestamp 文章末尾的时间戳，草稿和正式文章使用相同的格式，发布时会被更新
+func timestamp(t time.Time) string {
+	return fmt.Sprintf("> 更新时间: %s", t.Format(timestampLayout))
+}
+
+// defaultBody 新文章的正文
+func defaultBody(title string, now time.Time) string {
+	return fmt.Sprintf(`
+# %s
+
+在这里开始写你的文章内容...
+
+## 介绍
+
+简要介绍文章的主要内容。
+
+## 主要内容
+
+更多内容...
+
+## 总结
+
+总结你的观点和主要内容。
+
+---
+
+%s
+`, title, timestamp(now))
+}
+
+// updateTimestamp 更新文章末尾的时间戳，没有时间戳时在末尾添加
+func updateTimestamp(body string, now time.Time) string {
+	newTimestamp := timestamp(now)
+	if timestampRegex.MatchString(body) {
+		return timestampRegex.ReplaceAllString(body, newTimestamp)
+	}
+
+	body = strings.TrimRight(body, "\n")
+	return body + "\n\n---\n\n" + newTimestamp + "\n"
+}
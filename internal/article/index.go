@@ -0,0 +1,56 @@
+package article
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+// IndexFileName gen --per-dir 在每个标签目录中生成的索引文件名
+const IndexFileName = "README.md"
+
+// IndexMarker 生成的索引文件以该标记开头，用于与手写的 README.md 区分
+const IndexMarker = "<!-- myblog:index -->"
+
+// IsIndexFile 判断文件是否为 gen --per-dir 生成的索引文件，索引文件不是文章
+func IsIndexFile(fs afero.Fs, filePath string) bool {
+	if filepath.Base(filePath) != IndexFileName {
+		return false
+	}
+
+	file, err := fs.Open(filePath)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	head := make([]byte, len(IndexMarker))
+	if _, err := io.ReadFull(file, head); err != nil {
+		return false
+	}
+	return bytes.Equal(head, []byte(IndexMarker))
+}
+
+// IndexFiles 博客目录中所有生成的索引文件
+func (r *Repository) IndexFiles() []string {
+	var files []string
+	afero.Walk(r.FS, r.BlogsDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != r.BlogsDir && IsAssetsDir(info.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if IsIndexFile(r.FS, path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files
+}
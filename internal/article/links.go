@@ -0,0 +1,300 @@
+package article
+
+import (
+	"bytes"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/text"
+)
+
+// Link 正文中的链接或图片
+type Link struct {
+	Destination string
+	Image       bool
+}
+
+// RelativeLinks 找出正文中所有的相对链接和图片
+func RelativeLinks(body string) []Link {
+	source := []byte(body)
+	doc := goldmark.New(goldmark.WithExtensions(extension.GFM)).Parser().Parse(text.NewReader(source))
+
+	var links []Link
+	ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := node.(type) {
+		case *ast.Link:
+			if IsRelativeLink(string(n.Destination)) {
+				links = append(links, Link{Destination: string(n.Destination)})
+			}
+		case *ast.Image:
+			if IsRelativeLink(string(n.Destination)) {
+				links = append(links, Link{Destination: string(n.Destination), Image: true})
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return links
+}
+
+// IsRelativeLink 判断链接是否为指向本地文件的相对链接
+func IsRelativeLink(link string) bool {
+	if link == "" || strings.HasPrefix(link, "#") || strings.HasPrefix(link, "/") {
+		return false
+	}
+	u, err := url.Parse(link)
+	return err == nil && u.Scheme == "" && u.Host == ""
+}
+
+// EscapeDestination 转义链接地址中 Markdown 无法直接使用的字符
+func EscapeDestination(link string) string {
+	replacer := strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29")
+	return replacer.Replace(link)
+}
+
+// rewriteMovedLinks 文章从 oldPath 移动到 newPath 后，更新正文中的相对链接
+//
+// 两个路径需要相对于同一个目录。指向自身资源目录的链接改为新的资源目录名，
+// 其余相对链接按新的位置重新计算，保证仍然指向原来的文件。
+func rewriteMovedLinks(body, oldPath, newPath string) string {
+	oldDir, newDir := filepath.Dir(oldPath), filepath.Dir(newPath)
+	oldAssets, newAssets := AssetsDirName(oldPath), AssetsDirName(newPath)
+	if oldDir == newDir && oldAssets == newAssets {
+		return body
+	}
+
+	return rewriteLinks(body, func(destination string) (string, bool) {
+		u, err := url.Parse(destination)
+		if err != nil || u.Path == "" {
+			return "", false
+		}
+
+		var newLinkPath string
+		if u.Path == oldAssets || strings.HasPrefix(u.Path, oldAssets+"/") {
+			// 资源随文章一起移动，相对位置不变
+			newLinkPath = newAssets + strings.TrimPrefix(u.Path, oldAssets)
+		} else {
+			target := filepath.Join(oldDir, filepath.FromSlash(u.Path))
+			relPath, err := filepath.Rel(newDir, target)
+			if err != nil {
+				return "", false
+			}
+			newLinkPath = filepath.ToSlash(relPath)
+		}
+		if newLinkPath == u.Path {
+			return "", false
+		}
+
+		// 保留原链接中的查询参数和锚点
+		suffix := ""
+		if i := strings.IndexAny(destination, "?#"); i >= 0 {
+			suffix = destination[i:]
+		}
+		return EscapeDestination(newLinkPath) + suffix, true
+	})
+}
+
+// linkEdit 正文中需要替换的一段链接地址
+type linkEdit struct {
+	start, end  int
+	destination string
+}
+
+// rewriteLinks 用 rewrite 的结果替换正文中的相对链接地址，rewrite 返回 false 时保持不变
+//
+// 只替换 goldmark 解析出的链接、图片和链接引用定义中地址所在的字节，
+// 代码块和行内代码中的文字不会被修改。
+func rewriteLinks(body string, rewrite func(destination string) (string, bool)) string {
+	source := []byte(body)
+	doc := goldmark.New(goldmark.WithExtensions(extension.GFM)).Parser().Parse(text.NewReader(source))
+
+	var edits []linkEdit
+	add := func(destination []byte, start, end int) {
+		if start < 0 || !IsRelativeLink(string(destination)) {
+			return
+		}
+		if newDestination, ok := rewrite(string(destination)); ok {
+			edits = append(edits, linkEdit{start: start, end: end, destination: newDestination})
+		}
+	}
+
+	ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := node.(type) {
+		case *ast.Link:
+			if n.Reference == nil {
+				start, end := inlineDestination(source, n)
+				add(n.Destination, start, end)
+			}
+		case *ast.Image:
+			if n.Reference == nil {
+				start, end := inlineDestination(source, n)
+				add(n.Destination, start, end)
+			}
+		case *ast.LinkReferenceDefinition:
+			start, end := definitionDestination(source, n)
+			add(n.Destination, start, end)
+		}
+		return ast.WalkContinue, nil
+	})
+	if len(edits) == 0 {
+		return body
+	}
+
+	// 从后往前替换，前面的位置不受影响
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	for _, edit := range edits {
+		source = append(source[:edit.start:edit.start], append([]byte(edit.destination), source[edit.end:]...)...)
+	}
+	return string(source)
+}
+
+// inlineDestination 行内链接或图片 [文字](地址) 中地址的位置，找不到时返回 -1
+//
+// 从开头的 "[" 开始匹配方括号，跳过转义字符、行内代码和文字中嵌套的图片。
+func inlineDestination(source []byte, node ast.Node) (int, int) {
+	start := node.Pos()
+	if start < 0 {
+		return -1, -1
+	}
+	if start < len(source) && source[start] == '!' {
+		start++
+	}
+
+	depth := 0
+	for i := start; i < len(source); i++ {
+		switch source[i] {
+		case '\\':
+			i++
+		case '`':
+			i = skipCodeSpan(source, i)
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				if i+1 < len(source) && source[i+1] == '(' {
+					return destinationAt(source, skipSpace(source, i+2))
+				}
+				return -1, -1
+			}
+		}
+	}
+	return -1, -1
+}
+
+// skipCodeSpan 跳过从 i 开始的行内代码，返回结束的反引号的位置，没有结束的反引号时只跳过开头的反引号
+func skipCodeSpan(source []byte, i int) int {
+	n := 0
+	for i+n < len(source) && source[i+n] == '`' {
+		n++
+	}
+	fence := source[i : i+n]
+	for j := i + n; j < len(source); {
+		k := bytes.Index(source[j:], fence)
+		if k < 0 {
+			break
+		}
+		k += j
+		m := 0
+		for k+m < len(source) && source[k+m] == '`' {
+			m++
+		}
+		if m == n {
+			return k + m - 1
+		}
+		j = k + m
+	}
+	return i + n - 1
+}
+
+// definitionDestination 链接引用定义 [名称]: 地址 中地址的位置，找不到时返回 -1
+func definitionDestination(source []byte, node *ast.LinkReferenceDefinition) (int, int) {
+	lines := node.Lines()
+	if lines.Len() == 0 {
+		return -1, -1
+	}
+	for i := lines.At(0).Start; i < len(source); i++ {
+		switch source[i] {
+		case '\\':
+			i++
+		case ']':
+			if i+1 < len(source) && source[i+1] == ':' {
+				return destinationAt(source, skipSpace(source, i+2))
+			}
+		}
+	}
+	return -1, -1
+}
+
+// skipSpace 跳过空格、制表符和最多一个换行
+func skipSpace(source []byte, i int) int {
+	newline := false
+	for i < len(source) {
+		switch {
+		case source[i] == ' ' || source[i] == '\t':
+		case source[i] == '\n' && !newline:
+			newline = true
+		case source[i] == '\r' && !newline:
+			newline = true
+			if i+1 < len(source) && source[i+1] == '\n' {
+				i++
+			}
+		default:
+			return i
+		}
+		i++
+	}
+	return i
+}
+
+// destinationAt 从 start 开始的链接地址的位置，<地址> 形式只返回尖括号中的部分
+func destinationAt(source []byte, start int) (int, int) {
+	if start < len(source) && source[start] == '<' {
+		for i := start + 1; i < len(source); i++ {
+			switch source[i] {
+			case '\\':
+				i++
+			case '>':
+				return start + 1, i
+			case '\n':
+				return -1, -1
+			}
+		}
+		return -1, -1
+	}
+
+	depth := 0
+	i := start
+	for ; i < len(source); i++ {
+		c := source[i]
+		if c == '\\' {
+			i++
+			continue
+		}
+		if c <= ' ' {
+			break
+		}
+		if c == '(' {
+			depth++
+		} else if c == ')' {
+			if depth == 0 {
+				break
+			}
+			depth--
+		}
+	}
+	if i > len(source) {
+		i = len(source)
+	}
+	return start, i
+}
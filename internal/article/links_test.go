@@ -0,0 +1,55 @@
+package article
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRewriteMovedLinks(t *testing.T) {
+	body := strings.Join([]string{
+		"[同目录](b.md) 和 [锚点](b.md#用法) 和 [标题](b.md \"标题\")",
+		"[前缀](foo) 和 [共享前缀](foo/../../bar)",
+		"[![图](a.assets/1.png)](c.md)",
+		"![资源](a.assets/2.png) [空格](<my file.md>) [外部](https://example.com/b.md) [页内](#b)",
+		"`[行内代码](b.md)`",
+		"",
+		"```markdown",
+		"[link](b.md)",
+		"```",
+		"",
+		"    [缩进代码](b.md)",
+		"",
+		"[引用][ref]",
+		"",
+		"[ref]: b.md",
+		"",
+	}, "\n")
+
+	want := strings.Join([]string{
+		"[同目录](../../Go/b.md) 和 [锚点](../../Go/b.md#用法) 和 [标题](../../Go/b.md \"标题\")",
+		"[前缀](../../Go/foo) 和 [共享前缀](../../bar)",
+		"[![图](a.assets/1.png)](../../Go/c.md)",
+		"![资源](a.assets/2.png) [空格](<../../Go/my%20file.md>) [外部](https://example.com/b.md) [页内](#b)",
+		"`[行内代码](b.md)`",
+		"",
+		"```markdown",
+		"[link](b.md)",
+		"```",
+		"",
+		"    [缩进代码](b.md)",
+		"",
+		"[引用][ref]",
+		"",
+		"[ref]: ../../Go/b.md",
+		"",
+	}, "\n")
+
+	if got := rewriteMovedLinks(body, "Go/a.md", "Rust/Async/a.md"); got != want {
+		t.Errorf("rewriteMovedLinks:\n%s\nwant:\n%s", got, want)
+	}
+
+	// 改名后资源目录一起改名
+	if got := rewriteMovedLinks("![图](a.assets/1.png)\n", "Go/a.md", "Go/b.md"); got != "![图](b.assets/1.png)\n" {
+		t.Errorf("改名后 = %q", got)
+	}
+}
@@ -0,0 +1,500 @@
+package article
+
+import (
+	"MyBlog/internal/frontmatter"
+	"MyBlog/internal/slug"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// Repository 草稿目录和博客目录中的文章
+type Repository struct {
+	// DraftDir 草稿目录
+	DraftDir string
+	// BlogsDir 博客目录
+	BlogsDir string
+	// Format 新文章的 Front Matter 格式
+	Format frontmatter.Format
+	// Slug 根据标题生成新文章文件名的策略
+	Slug slug.Strategy
+	// TemplateDir 文章模板所在的目录
+	TemplateDir string
+	// Author 作者，模板中通过 {{ .Author }} 使用
+	Author string
+	// Config 模板中通过 {{ .Config }} 使用的配置
+	Config map[string]interface{}
+	// FS 文章所在的文件系统，目录都相对于它
+	//
+	// 磁盘上的文件（afero.OsFs 和 afero.BasePathFs）通过 fsys 修改，支持预演模式和事务日志；
+	// 内存文件系统直接修改；其他文件系统需要设置 DirectWrites 才能修改。
+	FS afero.Fs
+	// DirectWrites 直接通过 FS 修改文件，用于修改不会写入磁盘的文件系统，
+	// 例如以磁盘为只读底层、内存为上层的 afero.CopyOnWriteFs
+	DirectWrites bool
+}
+
+// NewRepository 创建使用当前目录所在磁盘的文章仓库，新文章默认使用 YAML Front Matter
+// 并保留标题原文作为文件名
+func NewRepository(draftDir, blogsDir string) *Repository {
+	return NewRepositoryFS(afero.NewOsFs(), draftDir, blogsDir)
+}
+
+// NewRepositoryFS 创建使用指定文件系统的文章仓库
+func NewRepositoryFS(fs afero.Fs, draftDir, blogsDir string) *Repository {
+	return &Repository{
+		FS:          fs,
+		DraftDir:    draftDir,
+		BlogsDir:    blogsDir,
+		Format:      frontmatter.YAML,
+		Slug:        slug.Keep,
+		TemplateDir: "templates",
+	}
+}
+
+// Root 文章状态对应的根目录
+func (r *Repository) Root(status Status) string {
+	if status == Draft {
+		return r.DraftDir
+	}
+	return r.BlogsDir
+}
+
+// CreateOptions 创建文章的参数
+type CreateOptions struct {
+	Title string
+	Tags  []string
+	// Slug 文件名和页面地址使用的名称，为空时根据标题生成
+	Slug string
+	// Template 文章模板的名称，为空时使用 default 模板（存在时）或内置的文章格式，
+	// BuiltinTemplate 总是使用内置的文章格式
+	Template string
+}
+
+// Create 在根目录的标签路径下创建文章，返回文件路径
+//
+// 正式文章 (Published) 会同时写入发布时间。
+func (r *Repository) Create(status Status, opts CreateOptions) (string, error) {
+	if err := ValidateTags(opts.Tags); err != nil {
+		return "", err
+	}
+
+	// 标签即目录结构：<根目录>/tag1/tag2/...
+	dirPath := filepath.Join(append([]string{r.Root(status)}, opts.Tags...)...)
+	if err := r.writer().MkdirAll(dirPath, 0755); err != nil {
+		return "", fmt.Errorf("创建目录失败: %v", err)
+	}
+
+	filePath := filepath.Join(dirPath, r.FileName(opts.Title, opts.Slug))
+	if _, err := r.FS.Stat(filePath); err == nil {
+		return "", fmt.Errorf("文件已存在: %s", filePath)
+	}
+
+	content, err := r.content(status, opts, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	if err := r.writer().WriteFile(filePath, content, 0644); err != nil {
+		return "", fmt.Errorf("写入文件失败: %v", err)
+	}
+	return filePath, nil
+}
+
+// FileName 根据标题生成文章文件名，slugOverride 不为空时优先使用
+func (r *Repository) FileName(title, slugOverride string) string {
+	if slugOverride != "" {
+		return slug.Make(slugOverride, slug.Keep) + ".md"
+	}
+	return slug.Make(title, r.Slug) + ".md"
+}
+
+// content 生成新文章的内容
+func (r *Repository) content(status Status, opts CreateOptions, now time.Time) ([]byte, error) {
+	name := opts.Template
+	if name == BuiltinTemplate {
+		name = ""
+	} else if name == "" {
+		if exists, _ := afero.Exists(r.FS, r.templatePath(DefaultTemplate)); exists {
+			name = DefaultTemplate
+		}
+	}
+	if name != "" {
+		content, err := r.renderTemplate(name, TemplateData{
+			Title:   opts.Title,
+			Tags:    opts.Tags,
+			TagPath: strings.Join(opts.Tags, "/"),
+			Slug:    opts.Slug,
+			Date:    now,
+			Status:  status,
+			Author:  r.Author,
+			Config:  r.Config,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return checkRendered(status, opts, now, content)
+	}
+
+	doc := frontmatter.New(r.Format)
+	doc.Set(frontmatter.KeyTitle, opts.Title)
+	doc.Set(frontmatter.KeyDate, now)
+	if status == Published {
+		doc.Set(frontmatter.KeyPublished, now)
+	}
+	doc.Set(frontmatter.KeyTags, opts.Tags)
+	if opts.Slug != "" {
+		doc.Set(frontmatter.KeySlug, slug.Make(opts.Slug, slug.Keep))
+	}
+
+	doc.Body = defaultBody(opts.Title, now)
+	return doc.Bytes()
+}
+
+// Find 按路径查找文章，返回绝对路径
+//
+// 路径可以相对于根目录，也可以带上根目录前缀，例如 Go/并发/channel.md 或
+// _draft/Go/并发/channel.md。
+func (r *Repository) Find(status Status, inputPath string) (string, error) {
+	rootDir := r.Root(status)
+	kind := status.kind()
+
+	// 规范化路径分隔符
+	inputPath = filepath.FromSlash(inputPath)
+
+	var fullPath string
+
+	// 判断输入路径是否包含目录前缀
+	if strings.HasPrefix(inputPath, rootDir+string(filepath.Separator)) ||
+		strings.HasPrefix(inputPath, "./"+rootDir+string(filepath.Separator)) ||
+		strings.HasPrefix(inputPath, ".\\"+rootDir+string(filepath.Separator)) {
+		// 如果包含目录前缀，直接使用该路径
+		fullPath = inputPath
+		// 去掉可能的 "./" 或 ".\\" 前缀
+		if strings.HasPrefix(fullPath, "./") || strings.HasPrefix(fullPath, ".\\") {
+			fullPath = fullPath[2:]
+		}
+	} else {
+		// 如果不包含目录前缀，拼接目录
+		fullPath = filepath.Join(rootDir, inputPath)
+	}
+
+	// 检查文件是否存在
+	if _, err := r.FS.Stat(fullPath); err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("%s文件不存在: %s", kind, inputPath)
+		}
+		return "", fmt.Errorf("访问文件失败: %v", err)
+	}
+
+	// 验证是否为 Markdown 文件
+	if !strings.HasSuffix(strings.ToLower(fullPath), ".md") {
+		return "", fmt.Errorf("指定的文件不是 Markdown 文件: %s", inputPath)
+	}
+
+	// 验证文件确实在指定目录中
+	absFullPath, err := r.Abs(fullPath)
+	if err != nil {
+		return "", fmt.Errorf("获取绝对路径失败: %v", err)
+	}
+
+	absRootDir, err := r.Abs(rootDir)
+	if err != nil {
+		return "", fmt.Errorf("获取%s目录绝对路径失败: %v", kind, err)
+	}
+
+	if !strings.HasPrefix(absFullPath, absRootDir+string(filepath.Separator)) {
+		return "", fmt.Errorf("指定文件不在%s目录中: %s", kind, inputPath)
+	}
+
+	return absFullPath, nil
+}
+
+// FindAny 先在草稿目录、再在博客目录中查找文章
+func (r *Repository) FindAny(inputPath string) (string, error) {
+	if articlePath, err := r.Find(Draft, inputPath); err == nil {
+		return articlePath, nil
+	}
+	articlePath, err := r.Find(Published, inputPath)
+	if err != nil {
+		return "", fmt.Errorf("在草稿目录和博客目录中都找不到文章: %s", inputPath)
+	}
+	return articlePath, nil
+}
+
+// Files 列出根目录（或其中某个标签路径）下的所有 Markdown 文件，跳过资源目录和生成的索引文件
+func (r *Repository) Files(status Status, tags ...string) []string {
+	dir := filepath.Join(append([]string{r.Root(status)}, tags...)...)
+
+	var files []string
+	afero.Walk(r.FS, dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if path != dir && IsAssetsDir(info.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(strings.ToLower(info.Name()), ".md") && !IsIndexFile(r.FS, path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+
+	return files
+}
+
+// List 解析根目录中的所有文章
+//
+// 无法解析的文章会被跳过，skip 不为 nil 时用于报告这些文章。
+func (r *Repository) List(status Status, skip func(path string, err error)) ([]*Article, error) {
+	rootDir := r.Root(status)
+	if _, err := r.FS.Stat(rootDir); os.IsNotExist(err) {
+		// 目录不存在时没有文章，不是错误
+		return nil, nil
+	}
+
+	var articles []*Article
+	for _, filePath := range r.Files(status) {
+		article, err := Load(r.FS, filePath)
+		if err != nil {
+			if skip != nil {
+				skip(filePath, err)
+			}
+			continue
+		}
+
+		article.Status = status
+		relPath, err := filepath.Rel(rootDir, filePath)
+		if err != nil {
+			relPath = filePath
+		}
+		article.RelPath = filepath.ToSlash(relPath)
+
+		articles = append(articles, article)
+	}
+	return articles, nil
+}
+
+// TagPaths 草稿目录和博客目录中已存在的完整标签路径，按字母排序
+func (r *Repository) TagPaths() []string {
+	tagPaths := make(map[string]bool)
+	r.scanTagPaths(r.BlogsDir, "", tagPaths)
+	r.scanTagPaths(r.DraftDir, "", tagPaths)
+
+	result := make([]string, 0, len(tagPaths))
+	for tagPath := range tagPaths {
+		if tagPath != "" {
+			result = append(result, tagPath)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// scanTagPaths 递归扫描目录获取完整标签路径
+func (r *Repository) scanTagPaths(dirPath string, currentPath string, tagPaths map[string]bool) {
+	dirs, err := afero.ReadDir(r.FS, dirPath)
+	if err != nil {
+		return
+	}
+
+	hasSubDirs := false
+	hasMarkdown := false
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			if strings.HasSuffix(strings.ToLower(dir.Name()), ".md") && !IsIndexFile(r.FS, filepath.Join(dirPath, dir.Name())) {
+				hasMarkdown = true
+			}
+			continue
+		}
+		// 文章的资源目录不是标签
+		if IsAssetsDir(dir.Name()) {
+			continue
+		}
+
+		hasSubDirs = true
+		newPath := dir.Name()
+		if currentPath != "" {
+			newPath = currentPath + "/" + dir.Name()
+		}
+		r.scanTagPaths(filepath.Join(dirPath, dir.Name()), newPath, tagPaths)
+	}
+
+	// 如果当前目录没有子目录，或者包含.md文件，则认为是一个完整的标签路径
+	if currentPath != "" && (!hasSubDirs || hasMarkdown) {
+		tagPaths[currentPath] = true
+	}
+}
+
+// isWithin 判断 target 是否位于 root 目录中
+func isWithin(root, target string) bool {
+	rel, err := filepath.Rel(filepath.Clean(root), filepath.Clean(target))
+	if err != nil || filepath.IsAbs(rel) {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// locate 找出文章所在的根目录，返回文章状态和相对于根目录的路径
+func (r *Repository) locate(articlePath string) (Status, string, error) {
+	absPath, err := r.Abs(articlePath)
+	if err != nil {
+		return "", "", fmt.Errorf("获取文章文件绝对路径失败: %v", err)
+	}
+
+	for _, status := range []Status{Draft, Published} {
+		absRoot, err := r.Abs(r.Root(status))
+		if err != nil {
+			return "", "", fmt.Errorf("获取%s目录绝对路径失败: %v", status.kind(), err)
+		}
+		if relPath, err := filepath.Rel(absRoot, absPath); err == nil && !strings.HasPrefix(relPath, "..") {
+			return status, relPath, nil
+		}
+	}
+	return "", "", fmt.Errorf("文章不在草稿目录或博客目录中: %s", articlePath)
+}
+
+// Publish 将草稿移动到博客目录中相同的标签路径下，返回新路径
+//
+// 发布时写入发布时间、删除定时发布时间并更新文章末尾的时间戳，资源目录随文章一起移动。
+func (r *Repository) Publish(draftPath string) (string, error) {
+	status, relPath, err := r.locate(draftPath)
+	if err != nil {
+		return "", err
+	}
+	if status != Draft {
+		return "", fmt.Errorf("文章不在草稿目录中: %s", draftPath)
+	}
+
+	now := time.Now()
+	return r.moveTo(draftPath, filepath.Join(r.BlogsDir, relPath), "目标文件已存在", func(doc *frontmatter.Document) error {
+		if err := doc.Set(frontmatter.KeyPublished, now); err != nil {
+			return err
+		}
+		// 已经发布，定时发布时间不再需要
+		doc.Delete(frontmatter.KeyPublishAt)
+		doc.Body = updateTimestamp(doc.Body, now)
+		return nil
+	})
+}
+
+// Unpublish 将已发布的文章移回草稿目录中相同的标签路径下，返回新路径
+//
+// 发布时间默认改名为 last_published 保留，strip 为 true 时直接删除。
+func (r *Repository) Unpublish(articlePath string, strip bool) (string, error) {
+	status, relPath, err := r.locate(articlePath)
+	if err != nil {
+		return "", err
+	}
+	if status != Published {
+		return "", fmt.Errorf("文章不在博客目录中: %s", articlePath)
+	}
+
+	return r.moveTo(articlePath, filepath.Join(r.DraftDir, relPath), "草稿文件已存在", func(doc *frontmatter.Document) error {
+		if strip {
+			doc.Delete(frontmatter.KeyPublished)
+		} else {
+			doc.Rename(frontmatter.KeyPublished, frontmatter.KeyLastPublished)
+		}
+		return nil
+	})
+}
+
+// Move 将文章移动到所在根目录下的标签路径中，返回新路径
+//
+// 移动时更新 Front Matter 中的标签，并重新计算正文中的相对链接。
+func (r *Repository) Move(articlePath string, tags []string) (string, error) {
+	if err := ValidateTags(tags); err != nil {
+		return "", err
+	}
+	status, relPath, err := r.locate(articlePath)
+	if err != nil {
+		return "", err
+	}
+
+	newRelPath := filepath.Join(append(tags, filepath.Base(articlePath))...)
+	if newRelPath == relPath {
+		return "", fmt.Errorf("文章已经在该标签路径中")
+	}
+	targetPath := filepath.Join(r.Root(status), newRelPath)
+	if !isWithin(r.Root(status), targetPath) {
+		return "", fmt.Errorf("目标路径不在%s目录中: %s", status.kind(), targetPath)
+	}
+
+	return r.moveTo(articlePath, targetPath, "目标文件已存在", func(doc *frontmatter.Document) error {
+		// 标签即目录结构
+		if len(tags) > 0 {
+			if err := doc.Set(frontmatter.KeyTags, tags); err != nil {
+				return err
+			}
+		} else {
+			doc.Delete(frontmatter.KeyTags)
+		}
+		return nil
+	})
+}
+
+// moveTo 修改文章后移动到 targetPath，目标文件已存在时返回以 exists 开头的错误
+//
+// 正文中的相对链接按新的位置重新计算，在草稿目录和博客目录之间移动时也仍然指向原来的文件。
+// 磁盘上的文件通过事务日志写入目标文件并删除原文件，中途退出时下次启动会自动恢复。
+func (r *Repository) moveTo(articlePath, targetPath, exists string, update func(doc *frontmatter.Document) error) (string, error) {
+	absPath, err := r.Abs(articlePath)
+	if err != nil {
+		return "", fmt.Errorf("获取文章文件绝对路径失败: %v", err)
+	}
+	absTarget, err := r.Abs(targetPath)
+	if err != nil {
+		return "", fmt.Errorf("获取目标文件绝对路径失败: %v", err)
+	}
+
+	// 不覆盖已存在的文件
+	if _, err := r.FS.Stat(targetPath); err == nil {
+		return "", fmt.Errorf("%s: %s", exists, targetPath)
+	}
+
+	content, err := afero.ReadFile(r.FS, absPath)
+	if err != nil {
+		return "", fmt.Errorf("读取文章文件失败: %v", err)
+	}
+
+	doc, err := frontmatter.Parse(content)
+	missing := errors.Is(err, frontmatter.ErrMissing)
+	if missing {
+		doc = frontmatter.New(frontmatter.YAML)
+		doc.Body = string(content)
+	} else if err != nil {
+		return "", fmt.Errorf("解析文章文件失败: %v", err)
+	}
+
+	if err := update(doc); err != nil {
+		return "", err
+	}
+	doc.Body = rewriteMovedLinks(doc.Body, absPath, absTarget)
+
+	// 没有 Front Matter 的文章只在需要写入字段时才补上
+	updatedContent := []byte(doc.Body)
+	if !missing || len(doc.Keys()) > 0 {
+		if updatedContent, err = doc.Bytes(); err != nil {
+			return "", err
+		}
+	}
+
+	if err := r.writer().MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+		return "", fmt.Errorf("创建目标目录失败: %v", err)
+	}
+	if err := r.moveWithAssets(absPath, targetPath, updatedContent); err != nil {
+		return "", fmt.Errorf("移动文章文件失败: %v", err)
+	}
+	return targetPath, nil
+}
@@ -0,0 +1,298 @@
+package article
+
+import (
+	"MyBlog/internal/frontmatter"
+	"MyBlog/internal/fsys"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+// newMemRepository 创建使用内存文件系统的文章仓库
+func newMemRepository() (*Repository, afero.Fs) {
+	fs := afero.NewMemMapFs()
+	return NewRepositoryFS(fs, "_draft", "blogs"), fs
+}
+
+func TestDraftPublishList(t *testing.T) {
+	repo, fs := newMemRepository()
+
+	draftPath, err := repo.Create(Draft, CreateOptions{Title: "Go 并发", Tags: []string{"Go", "并发"}})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if want := filepath.Join("_draft", "Go", "并发", "go_并发.md"); draftPath != want {
+		t.Fatalf("草稿路径 = %s, want %s", draftPath, want)
+	}
+
+	publishedPath, err := repo.Publish(draftPath)
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if exists, _ := afero.Exists(fs, draftPath); exists {
+		t.Errorf("发布后草稿仍然存在: %s", draftPath)
+	}
+
+	// gen 生成 README 前读取的文章
+	articles, err := repo.List(Published, func(path string, err error) {
+		t.Errorf("跳过了文章 %s: %v", path, err)
+	})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(articles) != 1 {
+		t.Fatalf("已发布的文章数量 = %d, want 1", len(articles))
+	}
+	a := articles[0]
+	if a.Path != publishedPath || a.RelPath != "Go/并发/go_并发.md" || a.Status != Published {
+		t.Errorf("文章 = %s (%s, %s)", a.Path, a.RelPath, a.Status)
+	}
+	if a.Title != "Go 并发" || strings.Join(a.Tags, "/") != "Go/并发" || strings.Join(a.DirTags(), "/") != "Go/并发" {
+		t.Errorf("Front Matter = %q %q", a.Title, a.Tags)
+	}
+	if a.Published.IsZero() {
+		t.Error("发布后缺少 published")
+	}
+
+	if drafts, _ := repo.List(Draft, nil); len(drafts) != 0 {
+		t.Errorf("草稿数量 = %d, want 0", len(drafts))
+	}
+	if tagPaths := repo.TagPaths(); strings.Join(tagPaths, ",") != "Go/并发" {
+		t.Errorf("TagPaths = %q", tagPaths)
+	}
+
+	// 撤回后回到草稿目录，保留原发布时间
+	draftPath, err = repo.Unpublish(publishedPath, false)
+	if err != nil {
+		t.Fatalf("Unpublish: %v", err)
+	}
+	doc, err := frontmatter.Parse(mustRead(t, fs, draftPath))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if doc.Has(frontmatter.KeyPublished) || !doc.Has(frontmatter.KeyLastPublished) {
+		t.Errorf("撤回后的字段 = %q", doc.Keys())
+	}
+}
+
+func TestListSkipsIndexFiles(t *testing.T) {
+	repo, fs := newMemRepository()
+	if _, err := repo.Create(Published, CreateOptions{Title: "基础", Tags: []string{"Go"}}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	index := filepath.Join("blogs", "Go", IndexFileName)
+	mustWrite(t, fs, index, IndexMarker+"\n# Go\n")
+	// 没有标记的 README.md 是普通文章
+	mustWrite(t, fs, filepath.Join("blogs", "Go", "并发", IndexFileName), "---\ntitle: 并发\n---\n")
+
+	articles, err := repo.List(Published, nil)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(articles) != 2 {
+		t.Errorf("文章数量 = %d, want 2", len(articles))
+	}
+	if files := repo.IndexFiles(); len(files) != 1 || files[0] != index {
+		t.Errorf("IndexFiles = %q", files)
+	}
+}
+
+func TestMoveWithAssets(t *testing.T) {
+	repo, fs := newMemRepository()
+	articlePath := filepath.Join("blogs", "Go", "channel.md")
+	mustWrite(t, fs, articlePath, "---\ntitle: channel\ntags: [Go]\n---\n\n![图](channel.assets/a.png)\n[基础](基础.md)\n")
+	mustWrite(t, fs, filepath.Join("blogs", "Go", "channel.assets", "a.png"), "png")
+
+	target, err := repo.Move(articlePath, []string{"Go", "并发"})
+	if err != nil {
+		t.Fatalf("Move: %v", err)
+	}
+	if want := filepath.Join("blogs", "Go", "并发", "channel.md"); target != want {
+		t.Fatalf("目标路径 = %s, want %s", target, want)
+	}
+	if exists, _ := afero.Exists(fs, filepath.Join("blogs", "Go", "并发", "channel.assets", "a.png")); !exists {
+		t.Error("资源目录没有一起移动")
+	}
+	if exists, _ := afero.DirExists(fs, filepath.Join("blogs", "Go", "channel.assets")); exists {
+		t.Error("原资源目录仍然存在")
+	}
+
+	content := string(mustRead(t, fs, target))
+	if !strings.Contains(content, "](channel.assets/a.png)") || !strings.Contains(content, "](../基础.md)") {
+		t.Errorf("移动后的正文:\n%s", content)
+	}
+	doc, _ := frontmatter.Parse([]byte(content))
+	if tags := strings.Join(doc.Strings(frontmatter.KeyTags), "/"); tags != "Go/并发" {
+		t.Errorf("tags = %s", tags)
+	}
+}
+
+// TestMoveWithAssetsOnDisk 磁盘上的文章和资源在同一个事务中移动，目标已存在时都不移动
+func TestMoveWithAssetsOnDisk(t *testing.T) {
+	t.Chdir(t.TempDir())
+	repo := NewRepositoryFS(afero.NewOsFs(), "_draft", "blogs")
+	fs := repo.FS
+	for _, dir := range []string{filepath.Join("blogs", "a.assets", "img"), filepath.Join("blogs", "Go")} {
+		if err := fs.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	source := filepath.Join("blogs", "a.md")
+	mustWrite(t, fs, source, "文章")
+	mustWrite(t, fs, filepath.Join("blogs", "a.assets", "img", "1.png"), "png")
+	mustWrite(t, fs, filepath.Join("blogs", "Go", "a.md"), "已存在")
+
+	if err := repo.moveWithAssets(source, filepath.Join("blogs", "Go", "a.md"), []byte("新文章")); err == nil {
+		t.Fatal("目标已存在时应返回错误")
+	}
+	if string(mustRead(t, fs, source)) != "文章" || string(mustRead(t, fs, filepath.Join("blogs", "a.assets", "img", "1.png"))) != "png" {
+		t.Error("失败后源文件被修改")
+	}
+	if exists, _ := afero.DirExists(fs, filepath.Join("blogs", "Go", "a.assets")); exists {
+		t.Error("失败后遗留了目标资源目录")
+	}
+
+	target := filepath.Join("blogs", "Go", "b.md")
+	if err := repo.moveWithAssets(source, target, []byte("新文章")); err != nil {
+		t.Fatalf("moveWithAssets: %v", err)
+	}
+	if string(mustRead(t, fs, target)) != "新文章" || string(mustRead(t, fs, filepath.Join("blogs", "Go", "b.assets", "img", "1.png"))) != "png" {
+		t.Error("文章或资源没有移动")
+	}
+	if exists, _ := afero.Exists(fs, filepath.Join("blogs", "a.assets")); exists {
+		t.Error("原资源目录仍然存在")
+	}
+}
+
+// TestBasePathFsUsesFsys 以磁盘为底层的 BasePathFs 通过 fsys 修改文件，支持预演模式
+func TestBasePathFsUsesFsys(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	repo := NewRepositoryFS(afero.NewBasePathFs(afero.NewOsFs(), dir), "_draft", "blogs")
+
+	fsys.SetDryRun(true)
+	_, err := repo.Create(Draft, CreateOptions{Title: "预演"})
+	fsys.SetDryRun(false)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Fatalf("预演模式修改了磁盘: %v", entries)
+	}
+
+	draftPath, err := repo.Create(Draft, CreateOptions{Title: "你好"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := repo.Publish(draftPath); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "blogs", "你好.md")); err != nil {
+		t.Errorf("发布的文章不在磁盘上: %v", err)
+	}
+
+	touched := strings.Join(fsys.Touched(), "\n")
+	if !strings.Contains(touched, filepath.Join(dir, "blogs", "你好.md")) {
+		t.Errorf("fsys 没有记录修改的文件:\n%s", touched)
+	}
+}
+
+// TestUnsupportedDiskFs 无法判断是否写入磁盘的文件系统没有设置 DirectWrites 时拒绝修改，只读文件系统同样无法修改
+func TestUnsupportedDiskFs(t *testing.T) {
+	dir := t.TempDir()
+	// 即使写入成功也只会写到临时目录中
+	t.Chdir(dir)
+	for _, fs := range []afero.Fs{
+		afero.NewCopyOnWriteFs(afero.NewBasePathFs(afero.NewOsFs(), dir), afero.NewOsFs()),
+		afero.NewReadOnlyFs(afero.NewOsFs()),
+	} {
+		repo := NewRepositoryFS(fs, "_draft", "blogs")
+		if _, err := repo.Create(Draft, CreateOptions{Title: "你好"}); err == nil {
+			t.Errorf("%T: Create 应返回错误", fs)
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("修改了磁盘: %v", entries)
+	}
+}
+
+func mustWrite(t *testing.T, fs afero.Fs, name, content string) {
+	t.Helper()
+	if err := afero.WriteFile(fs, name, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustRead(t *testing.T, fs afero.Fs, name string) []byte {
+	t.Helper()
+	content, err := afero.ReadFile(fs, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return content
+}
+
+// TestBuiltinTemplate 选择内置格式时不使用 default 模板
+func TestBuiltinTemplate(t *testing.T) {
+	repo, fs := newMemRepository()
+	repo.TemplateDir = "templates"
+	mustWrite(t, fs, filepath.Join("templates", DefaultTemplate+TemplateExt), "---\ntitle: {{ .Title | quote }}\n---\n\n来自 default 模板\n")
+
+	withDefault, err := repo.Create(Draft, CreateOptions{Title: "默认"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if content := string(mustRead(t, fs, withDefault)); !strings.Contains(content, "来自 default 模板") {
+		t.Errorf("没有使用 default 模板:\n%s", content)
+	}
+
+	builtin, err := repo.Create(Draft, CreateOptions{Title: "内置", Template: BuiltinTemplate})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if content := string(mustRead(t, fs, builtin)); strings.Contains(content, "来自 default 模板") {
+		t.Errorf("选择内置格式时使用了 default 模板:\n%s", content)
+	}
+
+	if names, _ := repo.Templates(); strings.Join(names, ",") != DefaultTemplate {
+		t.Errorf("Templates = %q", names)
+	}
+}
+
+// TestPublishRewritesLinks 在草稿目录和博客目录之间移动时，相对链接仍然指向原来的文件
+func TestPublishRewritesLinks(t *testing.T) {
+	repo, fs := newMemRepository()
+	mustWrite(t, fs, filepath.Join("blogs", "Go", "并发", "channel.md"), "---\ntitle: channel\n---\n")
+	articlePath := filepath.Join("blogs", "Go", "基础", "hello.md")
+	body := "[ref](../并发/channel.md) ![图](hello.assets/a.png) [首页](../../../README.md)\n"
+	mustWrite(t, fs, articlePath, "---\ntitle: hello\n---\n\n"+body)
+
+	draftPath, err := repo.Unpublish(articlePath, false)
+	if err != nil {
+		t.Fatalf("Unpublish: %v", err)
+	}
+	doc, err := frontmatter.Parse(mustRead(t, fs, draftPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "[ref](../../../blogs/Go/并发/channel.md) ![图](hello.assets/a.png) [首页](../../../README.md)\n"
+	if strings.TrimPrefix(doc.Body, "\n") != want {
+		t.Errorf("撤回后的正文 = %q, want %q", doc.Body, want)
+	}
+
+	publishedPath, err := repo.Publish(draftPath)
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	doc, err = frontmatter.Parse(mustRead(t, fs, publishedPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(strings.TrimPrefix(doc.Body, "\n"), body) {
+		t.Errorf("重新发布后的正文 = %q, want %q", doc.Body, body)
+	}
+}
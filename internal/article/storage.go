@@ -0,0 +1,206 @@
+package article
+
+import (
+	"MyBlog/internal/fsys"
+	"MyBlog/internal/journal"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+// writer 修改文件的操作
+type writer interface {
+	WriteFile(name string, data []byte, perm os.FileMode) error
+	// MoveAll 移动多个文件，任何一个目标文件已存在时都不移动
+	MoveAll(moves []journal.FileMove) error
+	MkdirAll(path string, perm os.FileMode) error
+	Remove(name string) error
+	RemoveAll(path string) error
+}
+
+// diskWriter 通过 fsys 修改磁盘上的文件，支持预演模式和事务日志
+//
+// base 不为 nil 时文件系统为 afero.BasePathFs，路径先转换为磁盘上的真实路径。
+type diskWriter struct {
+	base *afero.BasePathFs
+}
+
+// realPath 文件系统中的路径对应的磁盘路径
+func (w diskWriter) realPath(name string) (string, error) {
+	if w.base == nil {
+		return name, nil
+	}
+	return w.base.RealPath(name)
+}
+
+func (w diskWriter) WriteFile(name string, data []byte, perm os.FileMode) error {
+	name, err := w.realPath(name)
+	if err != nil {
+		return err
+	}
+	return fsys.WriteFile(name, data, perm)
+}
+
+func (w diskWriter) MoveAll(moves []journal.FileMove) error {
+	realMoves := make([]journal.FileMove, len(moves))
+	for i, move := range moves {
+		var err error
+		if move.Source, err = w.realPath(move.Source); err != nil {
+			return err
+		}
+		if move.Target, err = w.realPath(move.Target); err != nil {
+			return err
+		}
+		realMoves[i] = move
+	}
+	return fsys.MoveAll(realMoves)
+}
+
+func (w diskWriter) MkdirAll(path string, perm os.FileMode) error {
+	path, err := w.realPath(path)
+	if err != nil {
+		return err
+	}
+	return fsys.MkdirAll(path, perm)
+}
+
+func (w diskWriter) Remove(name string) error {
+	name, err := w.realPath(name)
+	if err != nil {
+		return err
+	}
+	return fsys.Remove(name)
+}
+
+func (w diskWriter) RemoveAll(path string) error {
+	path, err := w.realPath(path)
+	if err != nil {
+		return err
+	}
+	return fsys.RemoveAll(path)
+}
+
+// unsupportedWriter 拒绝修改无法通过 fsys 修改的磁盘文件，避免绕过预演模式和事务日志
+type unsupportedWriter struct {
+	fs afero.Fs
+}
+
+func (w unsupportedWriter) err() error {
+	return fmt.Errorf("不支持修改 %T 中的文件，请使用 afero.OsFs、afero.BasePathFs 或 afero.MemMapFs，修改不会写入磁盘时设置 DirectWrites", w.fs)
+}
+
+func (w unsupportedWriter) WriteFile(string, []byte, os.FileMode) error { return w.err() }
+
+func (w unsupportedWriter) MoveAll([]journal.FileMove) error { return w.err() }
+
+func (w unsupportedWriter) MkdirAll(string, os.FileMode) error { return w.err() }
+
+func (w unsupportedWriter) Remove(string) error { return w.err() }
+
+func (w unsupportedWriter) RemoveAll(string) error { return w.err() }
+
+// aferoWriter 直接修改 afero 文件系统中的文件
+type aferoWriter struct {
+	fs afero.Fs
+}
+
+func (w aferoWriter) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return afero.WriteFile(w.fs, name, data, perm)
+}
+
+// MoveAll 先写入所有目标文件再删除源文件，任何一个目标文件已存在时返回错误，写入失败时删除已写入的文件
+func (w aferoWriter) MoveAll(moves []journal.FileMove) error {
+	for _, move := range moves {
+		if _, err := w.fs.Stat(move.Target); err == nil {
+			return fmt.Errorf("%w: %s", journal.ErrTargetExists, move.Target)
+		}
+	}
+	for i, move := range moves {
+		if err := afero.WriteFile(w.fs, move.Target, move.Content, move.Perm); err != nil {
+			for _, written := range moves[:i] {
+				w.fs.Remove(written.Target)
+			}
+			return err
+		}
+	}
+	for _, move := range moves {
+		if err := w.fs.Remove(move.Source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w aferoWriter) MkdirAll(path string, perm os.FileMode) error {
+	return w.fs.MkdirAll(path, perm)
+}
+
+func (w aferoWriter) Remove(name string) error {
+	return w.fs.Remove(name)
+}
+
+func (w aferoWriter) RemoveAll(path string) error {
+	return w.fs.RemoveAll(path)
+}
+
+// onDisk 文件系统是否直接对应当前目录所在的磁盘
+func (r *Repository) onDisk() bool {
+	_, ok := r.FS.(*afero.OsFs)
+	return ok
+}
+
+// writer 根据文件系统的类型选择修改文件的方式
+//
+// afero.OsFs 和 afero.BasePathFs 视为磁盘，通过 fsys 修改以支持预演模式和事务日志；
+// afero.MemMapFs 和 afero.ReadOnlyFs（修改都会失败）直接修改。设置了 DirectWrites 时
+// 总是直接修改，例如以内存文件系统为上层的 afero.CopyOnWriteFs。其他文件系统无法判断
+// 修改是否会绕过预演模式写入磁盘，所以拒绝修改。
+func (r *Repository) writer() writer {
+	if r.DirectWrites {
+		return aferoWriter{fs: r.FS}
+	}
+	switch fs := r.FS.(type) {
+	case *afero.OsFs:
+		return diskWriter{}
+	case *afero.BasePathFs:
+		return diskWriter{base: fs}
+	case *afero.MemMapFs, *afero.ReadOnlyFs:
+		return aferoWriter{fs: r.FS}
+	}
+	return unsupportedWriter{fs: r.FS}
+}
+
+// ReadFile 读取文件系统中的文件
+func (r *Repository) ReadFile(name string) ([]byte, error) {
+	return afero.ReadFile(r.FS, name)
+}
+
+// Stat 获取文件系统中文件的信息
+func (r *Repository) Stat(name string) (os.FileInfo, error) {
+	return r.FS.Stat(name)
+}
+
+// WriteFile 写入文件，磁盘上的文件支持预演模式
+func (r *Repository) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return r.writer().WriteFile(name, data, perm)
+}
+
+// MkdirAll 创建目录
+func (r *Repository) MkdirAll(path string, perm os.FileMode) error {
+	return r.writer().MkdirAll(path, perm)
+}
+
+// Remove 删除文件
+func (r *Repository) Remove(name string) error {
+	return r.writer().Remove(name)
+}
+
+// Abs 磁盘上的路径转换为绝对路径，其他文件系统中只做规范化
+func (r *Repository) Abs(path string) (string, error) {
+	if r.onDisk() {
+		return filepath.Abs(path)
+	}
+	return filepath.Clean(path), nil
+}
@@ -0,0 +1,169 @@
+package article
+
+import (
+	"MyBlog/internal/frontmatter"
+	"MyBlog/internal/slug"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// TemplateExt 文章模板文件的扩展名，模板 <名称> 对应模板目录中的 <名称>.md.tmpl
+const TemplateExt = ".md.tmpl"
+
+// DefaultTemplate 没有指定模板时使用的模板名称，模板目录中没有该模板时使用内置的文章格式
+const DefaultTemplate = "default"
+
+// BuiltinTemplate 使用内置的文章格式，即使模板目录中有 default 模板
+const BuiltinTemplate = "builtin"
+
+// reservedTemplates 模板目录中不是文章模板的模板，例如 gen 使用的 readme.md.tmpl
+var reservedTemplates = map[string]bool{
+	"readme":        true,
+	BuiltinTemplate: true,
+}
+
+// TemplateData 文章模板中可以使用的数据
+type TemplateData struct {
+	Title string
+	Tags  []string
+	// TagPath 标签路径，例如 Go/并发
+	TagPath string
+	// Slug 通过 --slug 指定的名称，没有指定时为空
+	Slug   string
+	Date   time.Time
+	Status Status
+	Author string
+	// Config 全部配置，包括配置文件中自定义的配置项，例如 {{ .Config.site.title }}
+	Config map[string]interface{}
+}
+
+// templateFuncs 文章模板中可以使用的函数
+var templateFuncs = template.FuncMap{
+	// quote 生成带引号并转义的字符串，可以直接用于 YAML、TOML 和 JSON
+	"quote": func(s string) string {
+		data, _ := json.Marshal(s)
+		return string(data)
+	},
+	// json 生成 JSON，例如 {{ .Tags | json }} 生成 ["Go","并发"]
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	// date 按 Go 的时间格式格式化时间，例如 {{ .Date | date "2006-01-02" }}
+	"date": func(layout string, t time.Time) string {
+		return t.Format(layout)
+	},
+	// join 连接字符串，例如 {{ .Tags | join ", " }}
+	"join": func(sep string, s []string) string {
+		return strings.Join(s, sep)
+	},
+}
+
+// Templates 模板目录中所有文章模板的名称，模板目录不存在时返回空列表
+func (r *Repository) Templates() ([]string, error) {
+	entries, err := afero.ReadDir(r.FS, r.TemplateDir)
+	if err != nil {
+		if exists, _ := afero.DirExists(r.FS, r.TemplateDir); !exists {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取模板目录失败: %v", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), TemplateExt)
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), TemplateExt) && !reservedTemplates[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// templatePath 模板文件的路径
+func (r *Repository) templatePath(name string) string {
+	return filepath.Join(r.TemplateDir, name+TemplateExt)
+}
+
+// renderTemplate 渲染文章模板
+func (r *Repository) renderTemplate(name string, data TemplateData) ([]byte, error) {
+	content, err := afero.ReadFile(r.FS, r.templatePath(name))
+	if err != nil || reservedTemplates[name] {
+		names, _ := r.Templates()
+		if len(names) == 0 {
+			return nil, fmt.Errorf("模板不存在: %s (模板目录 %s 中没有 *%s 文件)", name, r.TemplateDir, TemplateExt)
+		}
+		return nil, fmt.Errorf("模板不存在: %s (可选: %s)", name, strings.Join(names, ", "))
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=zero").Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("解析模板失败: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("渲染模板失败: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// checkRendered 检查模板生成的文章，补上模板没有写入的必要字段
+//
+// Front Matter 必须存在且能够解析，标题不能为空，日期字段必须有效，标签必须与
+// 标签路径一致。模板没有写入的 date、tags、published（正式文章）和 slug 会自动补上。
+func checkRendered(status Status, opts CreateOptions, now time.Time, content []byte) ([]byte, error) {
+	doc, err := frontmatter.Parse(content)
+	switch {
+	case errors.Is(err, frontmatter.ErrMissing):
+		return nil, fmt.Errorf("模板生成的文章缺少Front Matter")
+	case err != nil:
+		return nil, fmt.Errorf("模板生成的Front Matter无效: %v", err)
+	}
+
+	if strings.TrimSpace(doc.String(frontmatter.KeyTitle)) == "" {
+		return nil, fmt.Errorf("模板生成的Front Matter缺少标题 (title)")
+	}
+	for _, key := range []string{frontmatter.KeyDate, frontmatter.KeyPublished, frontmatter.KeyUpdated, frontmatter.KeyLastmod, frontmatter.KeyPublishAt} {
+		if _, err := doc.TimeE(key); err != nil {
+			return nil, fmt.Errorf("模板生成的 %s 字段不是有效的日期: %v", key, doc.Get(key))
+		}
+	}
+
+	// 标签即目录结构
+	if doc.Has(frontmatter.KeyTags) {
+		tags := strings.Join(doc.Strings(frontmatter.KeyTags), "/")
+		if tags != strings.Join(opts.Tags, "/") {
+			return nil, fmt.Errorf("模板生成的标签 %q 与标签路径 %q 不一致", tags, strings.Join(opts.Tags, "/"))
+		}
+	} else if err := doc.Set(frontmatter.KeyTags, opts.Tags); err != nil {
+		return nil, err
+	}
+
+	if !doc.Has(frontmatter.KeyDate) {
+		if err := doc.Set(frontmatter.KeyDate, now); err != nil {
+			return nil, err
+		}
+	}
+	if status == Published && !doc.Has(frontmatter.KeyPublished) {
+		if err := doc.Set(frontmatter.KeyPublished, now); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Slug != "" && !doc.Has(frontmatter.KeySlug) {
+		if err := doc.Set(frontmatter.KeySlug, slug.Make(opts.Slug, slug.Keep)); err != nil {
+			return nil, err
+		}
+	}
+
+	return doc.Bytes()
+}
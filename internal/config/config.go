@@ -1,22 +1,61 @@
 package config
 
 import (
+	"MyBlog/internal/fsys"
 	"fmt"
-	"os"
+	"strings"
 
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/spf13/viper"
 )
 
+// EnvPrefix 环境变量前缀，例如 MYBLOG_DIRECTORIES_DRAFT 对应 directories.draft
+const EnvPrefix = "MYBLOG"
+
 // Config 配置结构体
 type Config struct {
+	Author      string `yaml:"author"`
 	Directories struct {
 		Draft string `yaml:"draft"`
 		Blogs string `yaml:"blogs"`
 	} `yaml:"directories"`
+	FrontMatter struct {
+		Format string `yaml:"format"`
+	} `yaml:"frontmatter"`
+	Site struct {
+		Title       string `yaml:"title"`
+		BaseURL     string `yaml:"base_url"`
+		Description string `yaml:"description"`
+	} `yaml:"site"`
+	Feed struct {
+		Limit int `yaml:"limit"`
+	} `yaml:"feed"`
+	Slug struct {
+		Strategy string `yaml:"strategy"`
+	} `yaml:"slug"`
+	Git struct {
+		AutoCommit bool   `yaml:"autocommit"`
+		Message    string `yaml:"message"`
+		Tag        string `yaml:"tag"`
+	} `yaml:"git"`
+	Templates struct {
+		Dir string `yaml:"dir"`
+	} `yaml:"templates"`
+	Readme struct {
+		Layout   string `yaml:"layout"`
+		MaxDepth int    `yaml:"max_depth"`
+		Anchor   string `yaml:"anchor"`
+	} `yaml:"readme"`
 }
 
 var AppConfig *Config
 
+// DefaultGitMessage 自动提交的默认提交信息模板
+const DefaultGitMessage = "{{.Command}}: {{.Title}}"
+
+// ReadmeLayouts gen 生成 README 时可用的文章分类布局
+var ReadmeLayouts = []string{"tree", "details", "flat"}
+
 // InitConfig 初始化配置
 func InitConfig() error {
 	viper.SetConfigName("config")
@@ -24,9 +63,28 @@ func InitConfig() error {
 	viper.AddConfigPath(".")
 	viper.AddConfigPath("$HOME/.myblog")
 
+	// 支持通过环境变量覆盖配置项
+	viper.SetEnvPrefix(EnvPrefix)
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.AutomaticEnv()
+
 	// 设置默认值
+	viper.SetDefault("author", "")
 	viper.SetDefault("directories.draft", "_draft")
 	viper.SetDefault("directories.blogs", "blogs")
+	viper.SetDefault("frontmatter.format", "yaml")
+	viper.SetDefault("site.title", "MyBlog")
+	viper.SetDefault("site.base_url", "")
+	viper.SetDefault("site.description", "")
+	viper.SetDefault("feed.limit", 20)
+	viper.SetDefault("slug.strategy", "keep")
+	viper.SetDefault("git.autocommit", false)
+	viper.SetDefault("git.message", DefaultGitMessage)
+	viper.SetDefault("git.tag", "")
+	viper.SetDefault("templates.dir", "templates")
+	viper.SetDefault("readme.layout", "flat")
+	viper.SetDefault("readme.max_depth", 0)
+	viper.SetDefault("readme.anchor", "github")
 
 	// 读取配置文件
 	if err := viper.ReadInConfig(); err != nil {
@@ -40,12 +98,19 @@ func InitConfig() error {
 		}
 	}
 
-	// 将配置解析到结构体
-	AppConfig = &Config{}
-	if err := viper.Unmarshal(AppConfig); err != nil {
+	return loadConfig()
+}
+
+// loadConfig 将 viper 中的配置解析到结构体
+func loadConfig() error {
+	cfg := &Config{}
+	if err := viper.Unmarshal(cfg, func(dc *mapstructure.DecoderConfig) {
+		dc.TagName = "yaml"
+	}); err != nil {
 		return fmt.Errorf("解析配置文件失败: %v", err)
 	}
 
+	AppConfig = cfg
 	return nil
 }
 
@@ -58,9 +123,13 @@ directories:
 `
 
 	configFile := "config.yaml"
-	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+	if err := fsys.WriteFile(configFile, []byte(configContent), 0644); err != nil {
 		return err
 	}
+	// 预演模式下文件没有真正创建，直接使用默认值
+	if fsys.DryRun() {
+		return nil
+	}
 
 	return viper.ReadInConfig()
 }
@@ -80,3 +149,120 @@ func GetBlogsDir() string {
 	}
 	return "blogs"
 }
+
+// GetAuthor 获取作者名称
+func GetAuthor() string {
+	if AppConfig != nil {
+		return AppConfig.Author
+	}
+	return ""
+}
+
+// GetFrontMatterFormat 获取新建文章使用的Front Matter格式 (yaml/toml/json)
+func GetFrontMatterFormat() string {
+	if AppConfig != nil && AppConfig.FrontMatter.Format != "" {
+		return AppConfig.FrontMatter.Format
+	}
+	return "yaml"
+}
+
+// GetSiteTitle 获取站点标题
+func GetSiteTitle() string {
+	if AppConfig != nil && AppConfig.Site.Title != "" {
+		return AppConfig.Site.Title
+	}
+	return "MyBlog"
+}
+
+// GetBaseURL 获取站点的访问地址，保证以 "/" 结尾
+func GetBaseURL() string {
+	if AppConfig == nil || AppConfig.Site.BaseURL == "" {
+		return ""
+	}
+	return strings.TrimRight(AppConfig.Site.BaseURL, "/") + "/"
+}
+
+// GetSiteDescription 获取站点简介
+func GetSiteDescription() string {
+	if AppConfig != nil {
+		return AppConfig.Site.Description
+	}
+	return ""
+}
+
+// GetFeedLimit 获取订阅源中最多包含的文章数量
+func GetFeedLimit() int {
+	if AppConfig != nil && AppConfig.Feed.Limit > 0 {
+		return AppConfig.Feed.Limit
+	}
+	return 20
+}
+
+// GetGitAutoCommit 是否在 new、pub、unpub、gen 之后自动提交
+func GetGitAutoCommit() bool {
+	if AppConfig != nil {
+		return AppConfig.Git.AutoCommit
+	}
+	return false
+}
+
+// GetGitMessage 获取自动提交的提交信息模板
+func GetGitMessage() string {
+	if AppConfig != nil && AppConfig.Git.Message != "" {
+		return AppConfig.Git.Message
+	}
+	return DefaultGitMessage
+}
+
+// GetGitTag 获取自动提交后创建的标签名模板，为空时不创建标签
+func GetGitTag() string {
+	if AppConfig != nil {
+		return AppConfig.Git.Tag
+	}
+	return ""
+}
+
+// GetSlugStrategy 获取根据标题生成文件名和页面地址的策略 (keep/pinyin/hash)
+func GetSlugStrategy() string {
+	if AppConfig != nil && AppConfig.Slug.Strategy != "" {
+		return AppConfig.Slug.Strategy
+	}
+	return "keep"
+}
+
+// GetTemplateDir 获取模板目录
+func GetTemplateDir() string {
+	if AppConfig != nil && AppConfig.Templates.Dir != "" {
+		return AppConfig.Templates.Dir
+	}
+	return "templates"
+}
+
+// GetReadmeLayout 获取 README 中文章分类的布局 (tree/details/flat)
+func GetReadmeLayout() string {
+	if AppConfig != nil && AppConfig.Readme.Layout != "" {
+		return AppConfig.Readme.Layout
+	}
+	return "flat"
+}
+
+// GetReadmeMaxDepth 获取 README 标签树的最大层数，0 表示不限制
+func GetReadmeMaxDepth() int {
+	if AppConfig != nil && AppConfig.Readme.MaxDepth > 0 {
+		return AppConfig.Readme.MaxDepth
+	}
+	return 0
+}
+
+// GetReadmeAnchor 获取 README 中标题锚点的生成规则 (github/gitlab)
+func GetReadmeAnchor() string {
+	if AppConfig != nil && AppConfig.Readme.Anchor != "" {
+		return AppConfig.Readme.Anchor
+	}
+	return "github"
+}
+
+// Settings 获取全部配置，包括配置文件中自定义的配置项，用于模板
+func Settings() map[string]interface{} {
+	return viper.AllSettings()
+}
@@ -0,0 +1,290 @@
+package config
+
+import (
+	"MyBlog/internal/anchor"
+	"MyBlog/internal/fsys"
+	"MyBlog/internal/slug"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// 配置项的来源
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+)
+
+// allowedValues 只能取固定值的配置项
+var allowedValues = map[string][]string{
+	"frontmatter.format": {"yaml", "toml", "json"},
+	"slug.strategy":      slug.Strategies,
+	"readme.layout":      ReadmeLayouts,
+	"readme.anchor":      anchor.Profiles,
+}
+
+// Keys 返回 Config 结构体中所有可用的配置项（按字母排序）
+func Keys() []string {
+	fields := make(map[string]reflect.Type)
+	collectKeys(reflect.TypeOf(Config{}), "", fields)
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// IsValidKey 判断配置项是否存在于 Config 结构体中
+func IsValidKey(key string) bool {
+	_, ok := keyType(key)
+	return ok
+}
+
+// collectKeys 递归收集结构体的 yaml 标签路径
+func collectKeys(t reflect.Type, prefix string, fields map[string]reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		if field.Type.Kind() == reflect.Struct {
+			collectKeys(field.Type, key, fields)
+			continue
+		}
+		fields[key] = field.Type
+	}
+}
+
+// keyType 获取配置项对应的字段类型
+func keyType(key string) (reflect.Type, bool) {
+	fields := make(map[string]reflect.Type)
+	collectKeys(reflect.TypeOf(Config{}), "", fields)
+	t, ok := fields[strings.ToLower(key)]
+	return t, ok
+}
+
+// EnvName 返回配置项对应的环境变量名
+func EnvName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// Source 返回配置项当前生效值的来源
+func Source(key string) string {
+	if _, ok := os.LookupEnv(EnvName(key)); ok {
+		return SourceEnv
+	}
+	if viper.InConfig(key) {
+		return SourceFile
+	}
+	return SourceDefault
+}
+
+// ConfigFile 返回当前使用的配置文件路径
+func ConfigFile() string {
+	configFile := viper.ConfigFileUsed()
+	if configFile == "" {
+		configFile = "config.yaml"
+	}
+	if absPath, err := filepath.Abs(configFile); err == nil {
+		return absPath
+	}
+	return configFile
+}
+
+// Set 设置配置项并写回配置文件（保留文件中的注释）
+func Set(key, value string) error {
+	key = strings.ToLower(key)
+	t, ok := keyType(key)
+	if !ok {
+		return fmt.Errorf("未知的配置项: %s", key)
+	}
+
+	if allowed, ok := allowedValues[key]; ok && !containsString(allowed, value) {
+		return fmt.Errorf("配置项 %s 的值无效: %s (可选: %s)", key, value, strings.Join(allowed, ", "))
+	}
+
+	node, err := valueNode(t, value)
+	if err != nil {
+		return fmt.Errorf("配置项 %s 的值无效: %v", key, err)
+	}
+
+	return editConfigFile(func(root *yaml.Node) {
+		setNode(root, strings.Split(key, "."), node)
+	})
+}
+
+// Unset 从配置文件中删除配置项，使其恢复默认值
+func Unset(key string) error {
+	key = strings.ToLower(key)
+	if !IsValidKey(key) {
+		return fmt.Errorf("未知的配置项: %s", key)
+	}
+
+	return editConfigFile(func(root *yaml.Node) {
+		unsetNode(root, strings.Split(key, "."))
+	})
+}
+
+// valueNode 按字段类型将字符串转换为 YAML 节点
+func valueNode(t reflect.Type, value string) (*yaml.Node, error) {
+	switch t.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("需要布尔值 (true/false)")
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(b)}, nil
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("需要整数")
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(n)}, nil
+	case reflect.Slice:
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle}
+		for _, item := range strings.Split(value, ",") {
+			item = strings.TrimSpace(item)
+			if item != "" {
+				seq.Content = append(seq.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: item})
+			}
+		}
+		return seq, nil
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}, nil
+	}
+}
+
+// editConfigFile 读取配置文件为 YAML 节点树，修改后写回并重新加载配置
+func editConfigFile(edit func(root *yaml.Node)) error {
+	configFile := ConfigFile()
+
+	content, err := os.ReadFile(configFile)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("读取配置文件失败: %v", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return fmt.Errorf("解析配置文件失败: %v", err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode}
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+
+	edit(doc.Content[0])
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(detectIndent(content))
+	if err := encoder.Encode(&doc); err != nil {
+		return fmt.Errorf("生成配置文件失败: %v", err)
+	}
+	encoder.Close()
+
+	if err := fsys.WriteFile(configFile, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("写入配置文件失败: %v", err)
+	}
+	if fsys.DryRun() {
+		return nil
+	}
+
+	viper.SetConfigFile(configFile)
+	if err := viper.ReadInConfig(); err != nil {
+		return fmt.Errorf("重新读取配置文件失败: %v", err)
+	}
+	return loadConfig()
+}
+
+// setNode 在映射节点中按路径设置值，缺失的中间层会自动创建
+func setNode(mapping *yaml.Node, path []string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			// 保留原值上的行尾注释
+			value.LineComment = mapping.Content[i+1].LineComment
+			mapping.Content[i+1] = value
+			return
+		}
+		child := mapping.Content[i+1]
+		if child.Kind != yaml.MappingNode {
+			child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			mapping.Content[i+1] = child
+		}
+		setNode(child, path[1:], value)
+		return
+	}
+
+	keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: path[0]}
+	if len(path) == 1 {
+		mapping.Content = append(mapping.Content, keyNode, value)
+		return
+	}
+	child := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	mapping.Content = append(mapping.Content, keyNode, child)
+	setNode(child, path[1:], value)
+}
+
+// unsetNode 按路径删除值，并清理因此变空的父级映射
+func unsetNode(mapping *yaml.Node, path []string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != path[0] {
+			continue
+		}
+		if len(path) > 1 {
+			child := mapping.Content[i+1]
+			if child.Kind != yaml.MappingNode {
+				return
+			}
+			unsetNode(child, path[1:])
+			if len(child.Content) > 0 {
+				return
+			}
+		}
+		mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+		return
+	}
+}
+
+// detectIndent 检测配置文件使用的缩进宽度，默认两个空格
+func detectIndent(content []byte) int {
+	for _, line := range strings.Split(string(content), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || trimmed == line || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		return len(line) - len(trimmed)
+	}
+	return 2
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,395 @@
+package frontmatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pelletier/go-toml/v2/unstable"
+	"gopkg.in/yaml.v3"
+)
+
+// Format Front Matter 格式
+type Format string
+
+const (
+	YAML Format = "yaml"
+	TOML Format = "toml"
+	JSON Format = "json"
+)
+
+// ParseFormat 解析格式名称，空字符串视为 YAML
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(name))) {
+	case "", YAML:
+		return YAML, nil
+	case TOML:
+		return TOML, nil
+	case JSON:
+		return JSON, nil
+	}
+	return "", fmt.Errorf("不支持的Front Matter格式: %s (可选: yaml, toml, json)", name)
+}
+
+// parseTOML 将 TOML 文本解析为映射节点，字段顺序与原文一致
+func parseTOML(text string) (*yaml.Node, error) {
+	var values map[string]interface{}
+	if err := toml.Unmarshal([]byte(text), &values); err != nil {
+		return nil, fmt.Errorf("解析Front Matter失败: %v", err)
+	}
+
+	order, err := tomlKeyOrder([]byte(text))
+	if err != nil {
+		return nil, fmt.Errorf("解析Front Matter失败: %v", err)
+	}
+
+	return mappingNode(values, order, "")
+}
+
+// tomlKeyOrder 记录每个键路径在 TOML 文本中第一次出现的位置
+func tomlKeyOrder(data []byte) (map[string]int, error) {
+	order := make(map[string]int)
+	record := func(path []string) {
+		for i := range path {
+			key := strings.Join(path[:i+1], ".")
+			if _, ok := order[key]; !ok {
+				order[key] = len(order)
+			}
+		}
+	}
+
+	var parser unstable.Parser
+	parser.Reset(data)
+
+	var table []string
+	for parser.NextExpression() {
+		expr := parser.Expression()
+		switch expr.Kind {
+		case unstable.Table, unstable.ArrayTable:
+			table = keyParts(expr.Key())
+			record(table)
+		case unstable.KeyValue:
+			path := append(append([]string{}, table...), keyParts(expr.Key())...)
+			record(path)
+		}
+	}
+	return order, parser.Error()
+}
+
+// keyParts 将点分隔的 TOML 键展开为字符串切片
+func keyParts(it unstable.Iterator) []string {
+	var parts []string
+	for it.Next() {
+		parts = append(parts, string(it.Node().Data))
+	}
+	return parts
+}
+
+// mappingNode 将解码后的 map 转换为 YAML 映射节点，键按 order 记录的顺序排列
+func mappingNode(values map[string]interface{}, order map[string]int, prefix string) (*yaml.Node, error) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	position := func(key string) int {
+		if p, ok := order[prefix+key]; ok {
+			return p
+		}
+		return math.MaxInt
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		pi, pj := position(keys[i]), position(keys[j])
+		if pi != pj {
+			return pi < pj
+		}
+		return keys[i] < keys[j]
+	})
+
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, key := range keys {
+		value, err := valueToNode(values[key], order, prefix+key+".")
+		if err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	}
+	return node, nil
+}
+
+// valueToNode 将 TOML 解码得到的值转换为 YAML 节点
+func valueToNode(value interface{}, order map[string]int, prefix string) (*yaml.Node, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return mappingNode(v, order, prefix)
+	case []interface{}:
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle}
+		for _, item := range v {
+			child, err := valueToNode(item, order, prefix)
+			if err != nil {
+				return nil, err
+			}
+			if child.Kind == yaml.MappingNode {
+				seq.Style = 0
+			}
+			seq.Content = append(seq.Content, child)
+		}
+		return seq, nil
+	case toml.LocalDate:
+		return encodeValue(v.AsTime(time.Local))
+	case toml.LocalDateTime:
+		return encodeValue(v.AsTime(time.Local))
+	case toml.LocalTime:
+		return encodeValue(v.String())
+	}
+	return encodeValue(value)
+}
+
+// parseJSONDocument 解析以 JSON 对象开头的文档
+func parseJSONDocument(text string, start int) (*Document, error) {
+	decoder := json.NewDecoder(strings.NewReader(text[start:]))
+	decoder.UseNumber()
+
+	token, err := decoder.Token()
+	if err != nil || token != json.Delim('{') {
+		return nil, ErrMissing
+	}
+	node, err := jsonObjectNode(decoder)
+	if err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, ErrUnclosed
+		}
+		return nil, fmt.Errorf("解析Front Matter失败: %v", err)
+	}
+
+	// 与 YAML 和 TOML 一致，右花括号所在行的换行属于 Front Matter，正文从下一行开始
+	end := start + int(decoder.InputOffset())
+	if line, next := readLine(text, end); strings.TrimSpace(line) == "" {
+		end = next
+	}
+	return &Document{
+		Format: JSON,
+		meta:   node,
+		raw:    text[:end],
+		Body:   text[end:],
+	}, nil
+}
+
+// jsonObjectNode 读取 JSON 对象的剩余部分（左花括号已被读取）
+func jsonObjectNode(decoder *json.Decoder) (*yaml.Node, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := token.(string)
+		if !ok {
+			return nil, fmt.Errorf("无效的键: %v", token)
+		}
+		value, err := jsonValueNode(decoder)
+		if err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	}
+	// 读取右花括号
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// jsonValueNode 读取一个 JSON 值并转换为 YAML 节点
+func jsonValueNode(decoder *json.Decoder) (*yaml.Node, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := token.(type) {
+	case json.Delim:
+		if v == '{' {
+			return jsonObjectNode(decoder)
+		}
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle}
+		for decoder.More() {
+			child, err := jsonValueNode(decoder)
+			if err != nil {
+				return nil, err
+			}
+			if child.Kind == yaml.MappingNode {
+				seq.Style = 0
+			}
+			seq.Content = append(seq.Content, child)
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return seq, nil
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: v.String()}, nil
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: v.String()}, nil
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+	return encodeValue(token)
+}
+
+// bareKeyPattern TOML 中无需引号的键
+var bareKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// encodeTOML 将映射节点写为 TOML，普通字段在前，子表在后
+func encodeTOML(w *bytes.Buffer, mapping *yaml.Node) error {
+	return encodeTOMLTable(w, mapping, nil)
+}
+
+func encodeTOMLTable(w *bytes.Buffer, mapping *yaml.Node, path []string) error {
+	var tables []int
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i+1].Kind == yaml.MappingNode {
+			tables = append(tables, i)
+			continue
+		}
+		value, err := tomlValue(mapping.Content[i+1])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s = %s\n", tomlKey(mapping.Content[i].Value), value)
+	}
+
+	for _, i := range tables {
+		tablePath := append(append([]string{}, path...), tomlKey(mapping.Content[i].Value))
+		fmt.Fprintf(w, "\n[%s]\n", strings.Join(tablePath, "."))
+		if err := encodeTOMLTable(w, mapping.Content[i+1], tablePath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func tomlKey(key string) string {
+	if bareKeyPattern.MatchString(key) {
+		return key
+	}
+	return quoteString(key)
+}
+
+// tomlValue 将节点格式化为 TOML 行内值
+func tomlValue(node *yaml.Node) (string, error) {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return tomlValue(node.Alias)
+	case yaml.SequenceNode:
+		items := make([]string, 0, len(node.Content))
+		for _, child := range node.Content {
+			item, err := tomlValue(child)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, item)
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case yaml.MappingNode:
+		items := make([]string, 0, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			item, err := tomlValue(node.Content[i+1])
+			if err != nil {
+				return "", err
+			}
+			items = append(items, tomlKey(node.Content[i].Value)+" = "+item)
+		}
+		return "{" + strings.Join(items, ", ") + "}", nil
+	}
+
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return "", err
+	}
+	switch v := value.(type) {
+	case nil:
+		// TOML 没有空值，使用空字符串代替
+		return `""`, nil
+	case string:
+		return quoteString(v), nil
+	case time.Time:
+		return v.Format(time.RFC3339), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	}
+	return quoteString(fmt.Sprint(value)), nil
+}
+
+// encodeJSON 将节点写为缩进格式的 JSON，保留字段顺序
+func encodeJSON(w *bytes.Buffer, node *yaml.Node, indent string) error {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return encodeJSON(w, node.Alias, indent)
+	case yaml.MappingNode:
+		if len(node.Content) == 0 {
+			w.WriteString("{}")
+			return nil
+		}
+		w.WriteString("{\n")
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			w.WriteString(indent + "  " + quoteString(node.Content[i].Value) + ": ")
+			if err := encodeJSON(w, node.Content[i+1], indent+"  "); err != nil {
+				return err
+			}
+			if i+2 < len(node.Content) {
+				w.WriteString(",")
+			}
+			w.WriteString("\n")
+		}
+		w.WriteString(indent + "}")
+		return nil
+	case yaml.SequenceNode:
+		w.WriteString("[")
+		for i, child := range node.Content {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			if err := encodeJSON(w, child, indent); err != nil {
+				return err
+			}
+		}
+		w.WriteString("]")
+		return nil
+	}
+
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return err
+	}
+	if t, ok := value.(time.Time); ok {
+		value = t.Format(time.RFC3339)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	w.Write(data)
+	return nil
+}
+
+// quoteString 生成带转义的双引号字符串，JSON 和 TOML 基础字符串通用
+func quoteString(s string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,325 @@
+// Package frontmatter 负责读写 Markdown 文章头部的 Front Matter。
+//
+// 支持 YAML (---)、TOML (+++) 和 JSON ({...}) 三种格式。文档被解析为元数据
+// 节点树和正文两部分，未知字段和字段顺序都会被保留，YAML 格式还会保留注释；
+// 未修改的 Front Matter 在序列化时按原文输出，YAML 格式中未修改的字段同样按原文输出。
+package frontmatter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Front Matter 的分隔符
+const (
+	yamlDelimiter = "---"
+	tomlDelimiter = "+++"
+)
+
+var (
+	// ErrMissing 文档开头没有 Front Matter
+	ErrMissing = errors.New("缺少Front Matter")
+	// ErrUnclosed Front Matter 没有结束分隔符
+	ErrUnclosed = errors.New("找不到完整的Front Matter")
+)
+
+// Document 一篇 Markdown 文档：Front Matter 加正文
+type Document struct {
+	// Format Front Matter 的格式，序列化时使用
+	Format Format
+
+	meta     *yaml.Node  // 元数据映射节点
+	raw      string      // 原始 Front Matter 文本（含分隔符）
+	source   *yamlSource // YAML Front Matter 中每个字段的原文
+	modified bool        // 元数据是否被修改过
+
+	// Body 正文内容（Front Matter 之后的全部内容）
+	Body string
+}
+
+// New 创建一篇没有任何元数据的新文档
+func New(format Format) *Document {
+	return &Document{
+		Format:   format,
+		meta:     &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"},
+		modified: true,
+	}
+}
+
+// ParseFile 读取并解析文件
+func ParseFile(filePath string) (*Document, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %v", err)
+	}
+	return Parse(content)
+}
+
+// Parse 将文档内容解析为 Front Matter 和正文
+func Parse(content []byte) (*Document, error) {
+	text := strings.TrimPrefix(string(content), "\ufeff")
+
+	// 允许 Front Matter 之前有空行
+	start := 0
+	for start < len(text) {
+		line, next := readLine(text, start)
+		if strings.TrimSpace(line) != "" {
+			break
+		}
+		start = next
+	}
+
+	line, pos := readLine(text, start)
+	switch strings.TrimRight(line, " \t\r") {
+	case yamlDelimiter:
+		return parseDelimited(text, pos, YAML)
+	case tomlDelimiter:
+		return parseDelimited(text, pos, TOML)
+	}
+	if strings.HasPrefix(line, "{") {
+		return parseJSONDocument(text, start)
+	}
+
+	return nil, ErrMissing
+}
+
+// parseDelimited 解析由分隔符包围的 YAML 或 TOML Front Matter，pos 为起始分隔符的下一行
+func parseDelimited(text string, pos int, format Format) (*Document, error) {
+	delimiter := yamlDelimiter
+	if format == TOML {
+		delimiter = tomlDelimiter
+	}
+
+	metaStart := pos
+	for pos < len(text) {
+		line, next := readLine(text, pos)
+		if strings.TrimRight(line, " \t\r") == delimiter {
+			var node *yaml.Node
+			var source *yamlSource
+			var err error
+			if format == TOML {
+				node, err = parseTOML(text[metaStart:pos])
+			} else if node, err = parseYAML(text[metaStart:pos]); err == nil {
+				source = newYAMLSource(node, text[:metaStart], text[metaStart:pos], text[pos:next])
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			return &Document{
+				Format: format,
+				meta:   node,
+				raw:    text[:next],
+				source: source,
+				Body:   text[next:],
+			}, nil
+		}
+		pos = next
+	}
+
+	return nil, ErrUnclosed
+}
+
+// readLine 返回从 pos 开始的一行（不含换行符）以及下一行的起始位置
+func readLine(text string, pos int) (string, int) {
+	end := strings.IndexByte(text[pos:], '\n')
+	if end < 0 {
+		return text[pos:], len(text)
+	}
+	return text[pos : pos+end], pos + end + 1
+}
+
+// parseYAML 将 YAML 文本解析为映射节点
+func parseYAML(text string) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(text), &doc); err != nil {
+		return nil, fmt.Errorf("解析Front Matter失败: %v", err)
+	}
+
+	// 空的 Front Matter
+	if len(doc.Content) == 0 {
+		return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", HeadComment: doc.HeadComment}, nil
+	}
+
+	node := doc.Content[0]
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("解析Front Matter失败: 顶层必须是键值映射")
+	}
+	if doc.HeadComment != "" {
+		node.HeadComment = strings.TrimSpace(doc.HeadComment + "\n" + node.HeadComment)
+	}
+	return node, nil
+}
+
+// Bytes 将文档序列化为文件内容
+func (d *Document) Bytes() ([]byte, error) {
+	if !d.modified && d.raw != "" {
+		return []byte(d.raw + d.Body), nil
+	}
+
+	if d.Format == YAML && d.source != nil {
+		content, err := d.source.patch(d.meta)
+		if err != nil {
+			return nil, fmt.Errorf("生成Front Matter失败: %v", err)
+		}
+		return []byte(content + d.Body), nil
+	}
+
+	var buf bytes.Buffer
+	switch d.Format {
+	case TOML:
+		buf.WriteString(tomlDelimiter + "\n")
+		if err := encodeTOML(&buf, d.meta); err != nil {
+			return nil, fmt.Errorf("生成Front Matter失败: %v", err)
+		}
+		buf.WriteString(tomlDelimiter + "\n")
+	case JSON:
+		if err := encodeJSON(&buf, d.meta, ""); err != nil {
+			return nil, fmt.Errorf("生成Front Matter失败: %v", err)
+		}
+		buf.WriteString("\n")
+	default:
+		buf.WriteString(yamlDelimiter + "\n")
+		if len(d.meta.Content) > 0 || d.meta.HeadComment != "" {
+			encoder := yaml.NewEncoder(&buf)
+			encoder.SetIndent(2)
+			if err := encoder.Encode(d.meta); err != nil {
+				return nil, fmt.Errorf("生成Front Matter失败: %v", err)
+			}
+			encoder.Close()
+		}
+		buf.WriteString(yamlDelimiter + "\n")
+	}
+	buf.WriteString(d.Body)
+
+	return buf.Bytes(), nil
+}
+
+// Keys 按原有顺序返回所有顶层字段名
+func (d *Document) Keys() []string {
+	keys := make([]string, 0, len(d.meta.Content)/2)
+	for i := 0; i+1 < len(d.meta.Content); i += 2 {
+		keys = append(keys, d.meta.Content[i].Value)
+	}
+	return keys
+}
+
+// Has 判断是否存在某个字段
+func (d *Document) Has(key string) bool {
+	return d.valueNode(key) != nil
+}
+
+// Get 获取字段的原始值，字段不存在时返回 nil
+func (d *Document) Get(key string) interface{} {
+	node := d.valueNode(key)
+	if node == nil {
+		return nil
+	}
+
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return node.Value
+	}
+	return value
+}
+
+// Decode 将整个 Front Matter 解码到结构体
+func (d *Document) Decode(v interface{}) error {
+	return d.meta.Decode(v)
+}
+
+// Set 设置字段的值，已存在的字段原地替换，新字段追加到末尾
+func (d *Document) Set(key string, value interface{}) error {
+	node, err := encodeValue(value)
+	if err != nil {
+		return fmt.Errorf("设置字段 %s 失败: %v", key, err)
+	}
+
+	d.modified = true
+	for i := 0; i+1 < len(d.meta.Content); i += 2 {
+		if d.meta.Content[i].Value == key {
+			// 保留原值上的注释
+			old := d.meta.Content[i+1]
+			node.LineComment = old.LineComment
+			node.FootComment = old.FootComment
+			d.meta.Content[i+1] = node
+			return nil
+		}
+	}
+
+	keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+	d.meta.Content = append(d.meta.Content, keyNode, node)
+	return nil
+}
+
+// Delete 删除字段，返回字段是否存在
+func (d *Document) Delete(key string) bool {
+	for i := 0; i+1 < len(d.meta.Content); i += 2 {
+		if d.meta.Content[i].Value == key {
+			d.meta.Content = append(d.meta.Content[:i], d.meta.Content[i+2:]...)
+			d.modified = true
+			return true
+		}
+	}
+	return false
+}
+
+// Rename 将字段改名，值和位置保持不变，返回字段是否存在
+//
+// 目标字段已存在时会被删除，避免出现重复的字段名。
+func (d *Document) Rename(oldKey, newKey string) bool {
+	if !d.Has(oldKey) {
+		return false
+	}
+	if oldKey == newKey {
+		return true
+	}
+
+	d.Delete(newKey)
+	for i := 0; i+1 < len(d.meta.Content); i += 2 {
+		if d.meta.Content[i].Value == oldKey {
+			d.meta.Content[i].Value = newKey
+			break
+		}
+	}
+	d.modified = true
+	return true
+}
+
+// valueNode 查找字段对应的值节点
+func (d *Document) valueNode(key string) *yaml.Node {
+	for i := 0; i+1 < len(d.meta.Content); i += 2 {
+		if d.meta.Content[i].Value == key {
+			return d.meta.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// encodeValue 将 Go 值转换为 YAML 节点，字符串统一使用双引号以保证特殊字符被正确转义
+func encodeValue(value interface{}) (*yaml.Node, error) {
+	switch v := value.(type) {
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v, Style: yaml.DoubleQuotedStyle}, nil
+	case []string:
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle}
+		for _, item := range v {
+			seq.Content = append(seq.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: item, Style: yaml.DoubleQuotedStyle})
+		}
+		return seq, nil
+	case time.Time:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!timestamp", Value: v.Format(time.RFC3339)}, nil
+	}
+
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return nil, err
+	}
+	return &node, nil
+}
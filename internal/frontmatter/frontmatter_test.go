@@ -0,0 +1,143 @@
+package frontmatter
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var roundTripDocuments = map[Format]string{
+	YAML: "---\ntitle: 并发\ntags:\n  - Go\n  - 并发\npublished: 2025-01-02T03:04:05Z\n---\n\n# 并发\n\n正文\n",
+	TOML: "+++\ntitle = \"并发\"\ntags = [\"Go\", \"并发\"]\npublished = 2025-01-02T03:04:05Z\n+++\n\n# 并发\n\n正文\n",
+	JSON: "{\n  \"title\": \"并发\",\n  \"tags\": [\"Go\", \"并发\"],\n  \"published\": \"2025-01-02T03:04:05Z\"\n}\n\n# 并发\n\n正文\n",
+}
+
+// TestBytesRoundTrip 修改后的文档重复解析和生成，内容保持不变，正文前不会多出空行
+func TestBytesRoundTrip(t *testing.T) {
+	for format, content := range roundTripDocuments {
+		t.Run(string(format), func(t *testing.T) {
+			doc, err := Parse([]byte(content))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if doc.Format != format {
+				t.Fatalf("Format = %s, want %s", doc.Format, format)
+			}
+
+			unchanged, err := doc.Bytes()
+			if err != nil {
+				t.Fatalf("Bytes: %v", err)
+			}
+			if string(unchanged) != content {
+				t.Errorf("未修改的文档发生了变化:\n%s", unchanged)
+			}
+
+			// 模拟 unpub/pub 反复修改同一篇文章
+			var previous []byte
+			for i := 0; i < 3; i++ {
+				if !doc.Rename(KeyPublished, KeyLastPublished) {
+					doc.Rename(KeyLastPublished, KeyPublished)
+				}
+				if err := doc.Set(KeyUpdated, time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)); err != nil {
+					t.Fatalf("Set: %v", err)
+				}
+
+				out, err := doc.Bytes()
+				if err != nil {
+					t.Fatalf("Bytes: %v", err)
+				}
+				if doc, err = Parse(out); err != nil {
+					t.Fatalf("第 %d 次修改后无法解析: %v\n%s", i+1, err, out)
+				}
+				if doc.Body != "\n# 并发\n\n正文\n" {
+					t.Errorf("第 %d 次修改后正文为 %q", i+1, doc.Body)
+				}
+				if i == 2 && string(out) != string(previous) {
+					// 第 1 次和第 3 次修改后的字段相同
+					t.Errorf("重复修改后内容不一致:\n%s\n---\n%s", previous, out)
+				}
+				if i == 0 {
+					previous = out
+				}
+
+				again, err := doc.Bytes()
+				if err != nil {
+					t.Fatalf("Bytes: %v", err)
+				}
+				if string(again) != string(out) {
+					t.Errorf("Parse(Bytes()) 不稳定:\n%s\n---\n%s", out, again)
+				}
+			}
+		})
+	}
+}
+
+// TestSetKeepsSource 修改或添加一个字段时，其他字段的注释、空行和引号保持原样
+func TestSetKeepsSource(t *testing.T) {
+	const head = "---\n" +
+		"# 文章信息\n" +
+		"title: \"他说\\\"你好\\\": Go\" # 标题\n" +
+		"summary: 'a: b'\n" +
+		"tags: [Go, 并发]\n" +
+		"\n" +
+		"# 发布设置\n" +
+		"draft: false\n" +
+		"notes: |\n" +
+		"  # 不是注释\n" +
+		"  第二行\n" +
+		"\n" +
+		"# 结尾注释\n"
+	content := head + "---\n\n正文\n"
+
+	doc, err := Parse([]byte(content))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if title := doc.String(KeyTitle); title != `他说"你好": Go` {
+		t.Fatalf("title = %q", title)
+	}
+	if err := doc.Set("slug", "go"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	out, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+	want := strings.TrimSuffix(head, "\n# 结尾注释\n") + "slug: \"go\"\n\n# 结尾注释\n---\n\n正文\n"
+	if string(out) != want {
+		t.Errorf("添加字段后:\n%s\nwant:\n%s", out, want)
+	}
+
+	// 修改已有字段时只有该字段变化，行尾注释保留
+	doc, _ = Parse([]byte(content))
+	if err := doc.Set("draft", true); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	out, _ = doc.Bytes()
+	want = strings.Replace(content, "draft: false\n", "draft: true\n", 1)
+	if string(out) != want {
+		t.Errorf("修改字段后:\n%s\nwant:\n%s", out, want)
+	}
+
+	doc, _ = Parse([]byte(content))
+	if err := doc.Set(KeyTitle, `她说"再见": Rust`); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	out, _ = doc.Bytes()
+	want = strings.Replace(content, `title: "他说\"你好\": Go" # 标题`, `title: "她说\"再见\": Rust" # 标题`, 1)
+	if string(out) != want {
+		t.Errorf("修改标题后:\n%s\nwant:\n%s", out, want)
+	}
+	if doc, err = Parse(out); err != nil || doc.String(KeyTitle) != `她说"再见": Rust` {
+		t.Errorf("重新解析标题 = %q, %v", doc.String(KeyTitle), err)
+	}
+
+	// 删除字段时去掉它之前的注释
+	doc, _ = Parse([]byte(content))
+	doc.Delete("draft")
+	out, _ = doc.Bytes()
+	want = strings.Replace(content, "\n# 发布设置\ndraft: false\n", "", 1)
+	if string(out) != want {
+		t.Errorf("删除字段后:\n%s\nwant:\n%s", out, want)
+	}
+}
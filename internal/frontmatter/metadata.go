@@ -0,0 +1,104 @@
+package frontmatter
+
+import (
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// 常用字段名
+const (
+	KeyTitle         = "title"
+	KeyDate          = "date"
+	KeyPublished     = "published"
+	KeyLastPublished = "last_published"
+	KeyPublishAt     = "publish_at"
+	KeyTags          = "tags"
+	KeyUpdated       = "updated"
+	KeyLastmod       = "lastmod"
+	KeySummary       = "summary"
+	KeyDescription   = "description"
+	KeyDraft         = "draft"
+	KeyUnlisted      = "unlisted"
+	KeySlug          = "slug"
+)
+
+// Metadata 文章的常用元数据
+type Metadata struct {
+	Title     string
+	Date      time.Time
+	Published time.Time
+	PublishAt time.Time
+	Updated   time.Time
+	Tags      []string
+	Summary   string
+	Draft     bool
+	Unlisted  bool
+	Slug      string
+}
+
+// Metadata 解析常用字段，格式不正确的字段保持零值
+func (d *Document) Metadata() Metadata {
+	return Metadata{
+		Title:     d.String(KeyTitle),
+		Date:      d.Time(KeyDate),
+		Published: d.Time(KeyPublished),
+		PublishAt: d.Time(KeyPublishAt),
+		Updated:   d.firstTime(KeyUpdated, KeyLastmod),
+		Tags:      d.Strings(KeyTags),
+		Summary:   d.firstString(KeySummary, KeyDescription),
+		Draft:     d.Bool(KeyDraft),
+		Unlisted:  d.Bool(KeyUnlisted),
+		Slug:      d.String(KeySlug),
+	}
+}
+
+// firstTime 返回第一个存在且有效的时间字段
+func (d *Document) firstTime(keys ...string) time.Time {
+	for _, key := range keys {
+		if t := d.Time(key); !t.IsZero() {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// firstString 返回第一个非空的字符串字段
+func (d *Document) firstString(keys ...string) string {
+	for _, key := range keys {
+		if s := d.String(key); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// String 以字符串形式获取字段值
+func (d *Document) String(key string) string {
+	return cast.ToString(d.Get(key))
+}
+
+// Strings 以字符串列表形式获取字段值
+func (d *Document) Strings(key string) []string {
+	return cast.ToStringSlice(d.Get(key))
+}
+
+// Bool 以布尔值形式获取字段值
+func (d *Document) Bool(key string) bool {
+	return cast.ToBool(d.Get(key))
+}
+
+// Time 以时间形式获取字段值
+func (d *Document) Time(key string) time.Time {
+	t, _ := d.TimeE(key)
+	return t
+}
+
+// TimeE 以时间形式获取字段值，字段存在但无法解析时返回错误
+func (d *Document) TimeE(key string) (time.Time, error) {
+	value := d.Get(key)
+	if value == nil {
+		return time.Time{}, nil
+	}
+	return cast.ToTimeE(value)
+}
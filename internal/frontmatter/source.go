@@ -0,0 +1,138 @@
+package frontmatter
+
+import (
+	"bytes"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlSource YAML Front Matter 的原文，修改后只重新生成改动过的字段
+//
+// 每个顶层字段的原文分为两部分：字段之前的空行和注释，以及字段本身（包括多行的值）。
+// 未修改的字段按原文输出，空行、注释、引号风格等都保持不变。
+type yamlSource struct {
+	head    string // 起始分隔符及之前的内容
+	fields  map[*yaml.Node]yamlField
+	trailer string // 最后一个字段之后的空行和注释
+	tail    string // 结束分隔符
+}
+
+// yamlField 一个顶层字段的原文
+type yamlField struct {
+	name  string
+	value *yaml.Node
+	lead  string // 字段之前的空行和注释
+	text  string // 字段本身
+}
+
+// newYAMLSource 按字段切分 YAML 原文，meta 为 text 解析得到的映射节点
+func newYAMLSource(meta *yaml.Node, head, text, tail string) *yamlSource {
+	if meta.Style&yaml.FlowStyle != 0 {
+		return nil
+	}
+
+	// 每行的起始位置，最后一项为文本末尾
+	starts := []int{0}
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' && i+1 < len(text) {
+			starts = append(starts, i+1)
+		}
+	}
+	starts = append(starts, len(text))
+	lineCount := len(starts) - 1
+	lineAt := func(n int) string { return text[starts[n]:starts[n+1]] }
+
+	source := &yamlSource{head: head, fields: make(map[*yaml.Node]yamlField), tail: tail}
+	prevEnd := 0 // 上一个字段之后的第一行
+	for i := 0; i+1 < len(meta.Content); i += 2 {
+		key := meta.Content[i]
+		first := key.Line - 1
+		next := lineCount
+		if i+2 < len(meta.Content) {
+			next = meta.Content[i+2].Line - 1
+		}
+		if first < prevEnd || next > lineCount || first >= next {
+			// 行号与原文不符，无法按原文输出
+			return nil
+		}
+
+		// 下一个字段之前顶格的空行和注释属于下一个字段
+		end := next
+		for end > first+1 && isGapLine(lineAt(end-1)) {
+			end--
+		}
+		source.fields[key] = yamlField{
+			name:  key.Value,
+			value: meta.Content[i+1],
+			lead:  text[starts[prevEnd]:starts[first]],
+			text:  text[starts[first]:starts[end]],
+		}
+		prevEnd = end
+	}
+	source.trailer = text[starts[prevEnd]:]
+	return source
+}
+
+// isGapLine 判断是否为字段之间顶格的空行或注释
+func isGapLine(line string) bool {
+	trimmed := strings.TrimRight(line, " \t\r\n")
+	return trimmed == "" || strings.HasPrefix(trimmed, "#")
+}
+
+// patch 按 meta 的当前内容生成 Front Matter，未修改的字段使用原文
+func (s *yamlSource) patch(meta *yaml.Node) (string, error) {
+	var buf strings.Builder
+	buf.WriteString(s.head)
+	for i := 0; i+1 < len(meta.Content); i += 2 {
+		key, value := meta.Content[i], meta.Content[i+1]
+		field, ok := s.fields[key]
+		if !ok {
+			// 新字段
+			text, err := encodeField(key, value)
+			if err != nil {
+				return "", err
+			}
+			if buf.Len() > 0 && !strings.HasSuffix(buf.String(), "\n") {
+				buf.WriteString("\n")
+			}
+			buf.WriteString(text)
+			continue
+		}
+
+		buf.WriteString(field.lead)
+		if field.name == key.Value && field.value == value {
+			buf.WriteString(field.text)
+			continue
+		}
+
+		// 修改过的字段，之前的注释已在原文中输出
+		keyCopy, valueCopy := *key, *value
+		keyCopy.HeadComment = ""
+		valueCopy.FootComment = ""
+		text, err := encodeField(&keyCopy, &valueCopy)
+		if err != nil {
+			return "", err
+		}
+		buf.WriteString(text)
+	}
+	if s.trailer != "" && !strings.HasSuffix(buf.String(), "\n") {
+		buf.WriteString("\n")
+	}
+	buf.WriteString(s.trailer)
+	buf.WriteString(s.tail)
+	return buf.String(), nil
+}
+
+// encodeField 生成一个顶层字段的 YAML
+func encodeField(key, value *yaml.Node) (string, error) {
+	mapping := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{key, value}}
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(mapping); err != nil {
+		return "", err
+	}
+	encoder.Close()
+	return buf.String(), nil
+}
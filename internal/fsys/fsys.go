@@ -0,0 +1,206 @@
+// Package fsys 所有修改项目文件的操作都通过这里进行
+//
+// 开启预演模式（--dry-run）后，写入、移动和删除只会输出将要进行的操作，
+// 改写已有文件时附带统一格式的差异，不会修改磁盘上的任何文件。
+package fsys
+
+import (
+	"MyBlog/internal/journal"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+var (
+	dryRun  bool
+	touched []string
+
+	// Output 预演模式的输出位置
+	Output io.Writer = os.Stdout
+)
+
+// SetDryRun 开启或关闭预演模式
+func SetDryRun(enabled bool) {
+	dryRun = enabled
+}
+
+// DryRun 是否处于预演模式
+func DryRun() bool {
+	return dryRun
+}
+
+// ResetTouched 开始新的操作，清空已记录的文件，例如 schedule 每次检查前调用
+func ResetTouched() {
+	touched = nil
+}
+
+// Touched 返回本次操作中创建、修改或删除过的文件
+func Touched() []string {
+	return append([]string(nil), touched...)
+}
+
+// touch 记录被修改的文件
+func touch(names ...string) {
+	for _, name := range names {
+		if absName, err := filepath.Abs(name); err == nil {
+			name = absName
+		}
+		touched = append(touched, name)
+	}
+}
+
+// WriteFile 通过事务日志原子地写入文件，中途退出时不会留下写了一半的文件，
+// 预演模式下只输出将要创建或改写的文件
+func WriteFile(name string, data []byte, perm os.FileMode) error {
+	touch(name)
+	if !dryRun {
+		return journal.WriteFile(name, data, perm)
+	}
+
+	old, err := os.ReadFile(name)
+	switch {
+	case os.IsNotExist(err):
+		report("创建", displayPath(name))
+	case err != nil:
+		return err
+	case bytes.Equal(old, data):
+		// 内容不变
+	default:
+		report("改写", displayPath(name))
+		printDiff(displayPath(name), displayPath(name), old, data)
+	}
+	return nil
+}
+
+// Move 写入 target 并删除 source，使用事务日志保证中途退出时可以恢复
+//
+// content 是写入目标文件的内容，与源文件不同时预演模式会输出差异。
+func Move(source, target string, content []byte, perm os.FileMode) error {
+	return MoveAll([]journal.FileMove{{Source: source, Target: target, Content: content, Perm: perm}})
+}
+
+// MoveAll 在一个事务中移动多个文件，例如文章和它的资源，中途退出时恢复后要么全部移动要么全部不变
+func MoveAll(moves []journal.FileMove) error {
+	for _, move := range moves {
+		touch(move.Source, move.Target)
+	}
+	if !dryRun {
+		return journal.MoveAll(moves)
+	}
+
+	for _, move := range moves {
+		if _, err := os.Stat(move.Target); err == nil {
+			return fmt.Errorf("%w: %s", journal.ErrTargetExists, move.Target)
+		}
+	}
+	for _, move := range moves {
+		report("移动", displayPath(move.Source)+" -> "+displayPath(move.Target))
+		if old, err := os.ReadFile(move.Source); err == nil && !bytes.Equal(old, move.Content) {
+			printDiff(displayPath(move.Source), displayPath(move.Target), old, move.Content)
+		}
+	}
+	return nil
+}
+
+// MkdirAll 创建目录，预演模式下不创建（目录会随文件的创建一起显示）
+func MkdirAll(path string, perm os.FileMode) error {
+	if dryRun {
+		return nil
+	}
+	return os.MkdirAll(path, perm)
+}
+
+// Remove 删除文件
+func Remove(name string) error {
+	touch(name)
+	if !dryRun {
+		return os.Remove(name)
+	}
+	if _, err := os.Stat(name); err != nil {
+		return err
+	}
+	report("删除", displayPath(name))
+	return nil
+}
+
+// RemoveAll 删除目录及其中的所有内容
+func RemoveAll(path string) error {
+	touch(path)
+	if !dryRun {
+		return os.RemoveAll(path)
+	}
+	if _, err := os.Stat(path); err == nil {
+		report("删除", displayPath(path))
+	}
+	return nil
+}
+
+// displayPath 将路径转换为相对于当前目录的形式，便于阅读
+func displayPath(name string) string {
+	if !filepath.IsAbs(name) {
+		return filepath.ToSlash(name)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return name
+	}
+	rel, err := filepath.Rel(wd, name)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return name
+	}
+	return filepath.ToSlash(rel)
+}
+
+// report 输出一条预演记录
+func report(action, target string) {
+	yellow := color.New(color.FgYellow).SprintFunc()
+	fmt.Fprintf(Output, "%s %s %s\n", yellow("[dry-run]"), action, target)
+}
+
+// printDiff 输出统一格式的差异，二进制文件只提示内容变化
+func printDiff(fromName, toName string, from, to []byte) {
+	if !utf8.Valid(from) || !utf8.Valid(to) {
+		fmt.Fprintln(Output, "  (二进制文件内容有变化)")
+		return
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(from)),
+		B:        difflib.SplitLines(string(to)),
+		FromFile: "a/" + fromName,
+		ToFile:   "b/" + toName,
+		Context:  3,
+	})
+	if err != nil || diff == "" {
+		return
+	}
+
+	red := color.New(color.FgRed).SprintFunc()
+	green := color.New(color.FgGreen).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		if line == "" {
+			continue
+		}
+		text := strings.TrimSuffix(line, "\n")
+		switch {
+		case strings.HasPrefix(text, "+++"), strings.HasPrefix(text, "---"):
+			fmt.Fprintln(Output, text)
+		case strings.HasPrefix(text, "@@"):
+			fmt.Fprintln(Output, cyan(text))
+		case strings.HasPrefix(text, "+"):
+			fmt.Fprintln(Output, green(text))
+		case strings.HasPrefix(text, "-"):
+			fmt.Fprintln(Output, red(text))
+		default:
+			fmt.Fprintln(Output, text)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+// Package git 调用本地的 git 命令提交命令修改过的文件
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrNotRepository 当前目录不在 git 仓库中
+var ErrNotRepository = errors.New("当前目录不是 git 仓库")
+
+// Repository 本地 git 仓库
+type Repository struct {
+	root string
+}
+
+// Open 打开当前目录所在的 git 仓库
+func Open() (*Repository, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("找不到 git 命令: %v", err)
+	}
+
+	out, err := run("", "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, ErrNotRepository
+	}
+	return &Repository{root: strings.TrimSpace(out)}, nil
+}
+
+// Root 仓库根目录
+func (r *Repository) Root() string {
+	return r.root
+}
+
+// Commit 只暂存并提交 files，返回实际提交的文件（相对于仓库根目录）
+//
+// 暂存区中已有其他文件时拒绝提交，避免把无关的修改一起提交。
+// 文件没有任何变化时不会创建提交，返回的列表为空。
+func (r *Repository) Commit(files []string, message string) ([]string, error) {
+	paths, err := r.relPaths(files)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	wanted := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		wanted[path] = true
+	}
+
+	staged, err := r.staged()
+	if err != nil {
+		return nil, err
+	}
+	var unrelated []string
+	for _, path := range staged {
+		if !wanted[path] {
+			unrelated = append(unrelated, path)
+		}
+	}
+	if len(unrelated) > 0 {
+		return nil, fmt.Errorf("暂存区中有无关的修改，请先提交或取消暂存: %s", strings.Join(unrelated, ", "))
+	}
+
+	// 已删除且从未被跟踪的文件不需要暂存
+	tracked, err := r.tracked(paths)
+	if err != nil {
+		return nil, err
+	}
+	var addPaths []string
+	for _, path := range paths {
+		if _, err := os.Stat(filepath.Join(r.root, path)); err == nil || tracked[path] {
+			addPaths = append(addPaths, path)
+		}
+	}
+	if len(addPaths) == 0 {
+		return nil, nil
+	}
+
+	if _, err := run(r.root, append([]string{"add", "--all", "--"}, addPaths...)...); err != nil {
+		return nil, fmt.Errorf("暂存文件失败: %v", err)
+	}
+
+	committed, err := r.staged()
+	if err != nil {
+		return nil, err
+	}
+	if len(committed) == 0 {
+		return nil, nil
+	}
+
+	if _, err := run(r.root, "commit", "--quiet", "-m", message); err != nil {
+		return nil, fmt.Errorf("提交失败: %v", err)
+	}
+	return committed, nil
+}
+
+// CheckStaged 暂存区中已有修改时返回错误
+//
+// 在命令修改文件之前调用，避免文件修改后才发现无法提交。
+func (r *Repository) CheckStaged() error {
+	staged, err := r.staged()
+	if err != nil {
+		return err
+	}
+	if len(staged) > 0 {
+		return fmt.Errorf("暂存区中有其他修改，请先提交或取消暂存: %s", strings.Join(staged, ", "))
+	}
+	return nil
+}
+
+// Tag 在当前提交上创建轻量标签
+func (r *Repository) Tag(name string) error {
+	if _, err := run(r.root, "tag", name); err != nil {
+		return fmt.Errorf("创建标签失败: %v", err)
+	}
+	return nil
+}
+
+// staged 暂存区中的文件
+func (r *Repository) staged() ([]string, error) {
+	out, err := run(r.root, "diff", "--cached", "--name-only", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("读取暂存区失败: %v", err)
+	}
+	return splitNull(out), nil
+}
+
+// tracked 返回 paths 中已被跟踪的文件
+func (r *Repository) tracked(paths []string) (map[string]bool, error) {
+	out, err := run(r.root, append([]string{"ls-files", "-z", "--"}, paths...)...)
+	if err != nil {
+		return nil, fmt.Errorf("读取跟踪的文件失败: %v", err)
+	}
+	tracked := make(map[string]bool)
+	for _, path := range splitNull(out) {
+		tracked[path] = true
+	}
+	return tracked, nil
+}
+
+// relPaths 将文件路径转换为相对于仓库根目录的路径，去重并排序
+func (r *Repository) relPaths(files []string) ([]string, error) {
+	root, err := filepath.EvalSymlinks(r.root)
+	if err != nil {
+		return nil, fmt.Errorf("解析仓库路径失败: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	var paths []string
+	for _, file := range files {
+		absFile, err := filepath.Abs(file)
+		if err != nil {
+			return nil, fmt.Errorf("获取绝对路径失败: %v", err)
+		}
+		// 文件可能已被删除，只解析所在目录的符号链接
+		if dir, err := filepath.EvalSymlinks(filepath.Dir(absFile)); err == nil {
+			absFile = filepath.Join(dir, filepath.Base(absFile))
+		}
+
+		rel, err := filepath.Rel(root, absFile)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("文件不在 git 仓库中: %s", file)
+		}
+		rel = filepath.ToSlash(rel)
+		if !seen[rel] {
+			seen[rel] = true
+			paths = append(paths, rel)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// run 在 dir 中执行 git 命令，返回标准输出
+func run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", errors.New(msg)
+		}
+		return "", err
+	}
+	return stdout.String(), nil
+}
+
+func splitNull(out string) []string {
+	var items []string
+	for _, item := range strings.Split(out, "\x00") {
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
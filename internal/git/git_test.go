@@ -0,0 +1,86 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestRepository 在临时目录中创建 git 仓库并切换到该目录
+func newTestRepository(t *testing.T) *Repository {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("找不到 git 命令")
+	}
+	dir := t.TempDir()
+	t.Chdir(dir)
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"config", "user.name", "myblog"},
+		{"config", "user.email", "myblog@example.com"},
+	} {
+		if _, err := run(dir, args...); err != nil {
+			t.Fatalf("git %s: %v", strings.Join(args, " "), err)
+		}
+	}
+
+	repo, err := Open()
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	return repo
+}
+
+// TestCheckStaged 暂存区中有修改时，命令在修改文件之前就能发现无法提交
+func TestCheckStaged(t *testing.T) {
+	repo := newTestRepository(t)
+	if err := repo.CheckStaged(); err != nil {
+		t.Fatalf("空的暂存区: %v", err)
+	}
+
+	if err := os.WriteFile("other.txt", []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := run(repo.Root(), "add", "other.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CheckStaged(); err == nil || !strings.Contains(err.Error(), "other.txt") {
+		t.Errorf("CheckStaged = %v, want 包含 other.txt 的错误", err)
+	}
+}
+
+// TestCommitOnlyFiles 只提交传入的文件，删除的文件也会提交
+func TestCommitOnlyFiles(t *testing.T) {
+	repo := newTestRepository(t)
+	for _, name := range []string{"a.md", "b.md"} {
+		if err := os.WriteFile(name, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	committed, err := repo.Commit([]string{"a.md", filepath.Join("missing", "c.md")}, "pub: a")
+	if err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if strings.Join(committed, ",") != "a.md" {
+		t.Errorf("committed = %q, want a.md", committed)
+	}
+
+	if err := os.Remove("a.md"); err != nil {
+		t.Fatal(err)
+	}
+	committed, err = repo.Commit([]string{"a.md"}, "unpub: a")
+	if err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if strings.Join(committed, ",") != "a.md" {
+		t.Errorf("committed = %q, want a.md", committed)
+	}
+
+	status, _ := run(repo.Root(), "status", "--porcelain")
+	if strings.TrimSpace(status) != "?? b.md" {
+		t.Errorf("git status = %q", status)
+	}
+}
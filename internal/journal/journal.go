@@ -0,0 +1,369 @@
+// Package journal 为文件的移动和改写提供崩溃安全的事务日志
+//
+// 每个操作开始前先在 .myblog/journal/ 下记录日志，内容写入同目录的临时文件并
+// fsync 后再原子地重命名为目标文件，最后删除源文件和日志。程序中途退出时，
+// 下次启动调用 Recover 即可根据日志把未完成的操作补完或回滚。
+//
+// 一个操作可以包含多个文件，例如文章和它的资源目录，恢复时一起补完或回滚。
+// 操作和恢复都持有 .myblog/lock 文件锁，一个进程恢复时不会回滚另一个进程正在进行的操作。
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dir 项目中保存工具状态的目录
+const Dir = ".myblog"
+
+// 操作的阶段
+const (
+	// StagePrepared 日志已记录，临时文件可能不完整，恢复时回滚
+	StagePrepared = "prepared"
+	// StageCommitted 临时文件已完整写入，恢复时继续完成
+	StageCommitted = "committed"
+)
+
+// ErrTargetExists 目标文件已存在
+var ErrTargetExists = errors.New("目标文件已存在")
+
+// FileMove 一个文件的移动：将 Content 写入 Target 并删除 Source
+type FileMove struct {
+	Source  string
+	Target  string
+	Content []byte
+	Perm    os.FileMode
+}
+
+// Entry 操作中的一个文件
+type Entry struct {
+	Source string `json:"source,omitempty"` // 完成后需要删除的源文件，改写文件时为空
+	Target string `json:"target"`
+	Temp   string `json:"temp"`
+}
+
+// Operation 一次文件操作的日志记录
+type Operation struct {
+	ID      string    `json:"id"`
+	Stage   string    `json:"stage"`
+	Entries []Entry   `json:"entries"`
+	Time    time.Time `json:"time"`
+
+	path string
+}
+
+// Target 第一个文件的目标路径，用于提示
+func (op *Operation) Target() string {
+	if len(op.Entries) == 0 {
+		return ""
+	}
+	return op.Entries[0].Target
+}
+
+// journalDir 日志文件所在目录
+func journalDir() string {
+	return filepath.Join(Dir, "journal")
+}
+
+// Move 将 content 写入 target 并删除 source，target 已存在时返回 ErrTargetExists
+//
+// content 通常是 source 修改后的内容，例如发布时更新了发布时间的草稿。
+// 检查之后才出现的 target 同样不会被覆盖。
+func Move(source, target string, content []byte, perm os.FileMode) error {
+	return MoveAll([]FileMove{{Source: source, Target: target, Content: content, Perm: perm}})
+}
+
+// MoveAll 在一个操作中移动多个文件，任何一个目标文件已存在时都不移动并返回 ErrTargetExists
+//
+// 程序中途退出时，恢复后要么所有文件都已移动，要么所有源文件都保持不变。
+func MoveAll(moves []FileMove) error {
+	for _, move := range moves {
+		if _, err := os.Stat(move.Target); err == nil {
+			return fmt.Errorf("%w: %s", ErrTargetExists, move.Target)
+		}
+	}
+	return run(moves, true)
+}
+
+// WriteFile 原子地写入文件，中途退出时不会留下写了一半的文件
+func WriteFile(target string, content []byte, perm os.FileMode) error {
+	return run([]FileMove{{Target: target, Content: content, Perm: perm}}, false)
+}
+
+// run 执行操作，move 为 true 时不覆盖已存在的目标文件
+func run(moves []FileMove, move bool) error {
+	unlock, err := lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	id := strconv.FormatInt(time.Now().UnixNano(), 36)
+	op := &Operation{
+		ID:    id,
+		Stage: StagePrepared,
+		Time:  time.Now(),
+		path:  filepath.Join(journalDir(), id+".json"),
+	}
+	for _, m := range moves {
+		absTarget, err := filepath.Abs(m.Target)
+		if err != nil {
+			return fmt.Errorf("获取目标文件绝对路径失败: %v", err)
+		}
+		absSource := ""
+		if m.Source != "" {
+			if absSource, err = filepath.Abs(m.Source); err != nil {
+				return fmt.Errorf("获取源文件绝对路径失败: %v", err)
+			}
+		} else if move {
+			return fmt.Errorf("缺少源文件: %s", m.Target)
+		}
+		op.Entries = append(op.Entries, Entry{
+			Source: absSource,
+			Target: absTarget,
+			Temp:   filepath.Join(filepath.Dir(absTarget), "."+filepath.Base(absTarget)+".myblog-"+id),
+		})
+	}
+
+	if err := os.MkdirAll(journalDir(), 0755); err != nil {
+		return fmt.Errorf("创建日志目录失败: %v", err)
+	}
+	if err := op.save(); err != nil {
+		return err
+	}
+
+	// 写入临时文件，失败时回滚
+	for i, m := range moves {
+		if err := writeSynced(op.Entries[i].Temp, m.Content, m.Perm); err != nil {
+			op.rollback()
+			return fmt.Errorf("写入临时文件失败: %v", err)
+		}
+	}
+
+	op.Stage = StageCommitted
+	if err := op.save(); err != nil {
+		op.rollback()
+		return err
+	}
+
+	if err := op.complete(); err != nil {
+		if errors.Is(err, ErrTargetExists) {
+			op.rollback()
+		}
+		return err
+	}
+	return nil
+}
+
+// Pending 返回所有未完成的操作
+func Pending() ([]*Operation, error) {
+	entries, err := os.ReadDir(journalDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取日志目录失败: %v", err)
+	}
+
+	var ops []*Operation
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(journalDir(), entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("读取日志失败: %v", err)
+		}
+		op := &Operation{path: path}
+		if err := json.Unmarshal(data, op); err != nil {
+			// 日志本身没有写完，对应的操作还未开始
+			op.Stage = StagePrepared
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+// Recover 补完或回滚上次中断的操作，返回处理过的操作
+//
+// 移动操作的目标文件已被其他文件占用时回滚，源文件保持不变。
+func Recover() ([]*Operation, error) {
+	// 没有日志目录时不需要加锁，也不创建 .myblog 目录
+	if _, err := os.Stat(journalDir()); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	unlock, err := lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	ops, err := Pending()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range ops {
+		if op.Stage == StageCommitted {
+			err = op.complete()
+			if errors.Is(err, ErrTargetExists) {
+				err = op.rollback()
+			}
+		} else {
+			err = op.rollback()
+		}
+		if err != nil {
+			return ops, fmt.Errorf("恢复操作失败 %s: %v", op.Target(), err)
+		}
+	}
+	return ops, nil
+}
+
+// complete 将临时文件放到目标位置并删除源文件，可以重复执行
+//
+// 所有目标文件都就位后才删除临时文件和源文件。移动文件时不覆盖已存在的目标文件，
+// 返回 ErrTargetExists 并保留临时文件，由调用方回滚。
+func (op *Operation) complete() error {
+	for _, entry := range op.Entries {
+		if _, err := os.Stat(entry.Temp); err != nil {
+			continue
+		}
+		if entry.Source == "" {
+			if err := os.Rename(entry.Temp, entry.Target); err != nil {
+				return fmt.Errorf("重命名临时文件失败: %v", err)
+			}
+		} else if err := linkNoReplace(entry.Temp, entry.Target); err != nil {
+			if errors.Is(err, ErrTargetExists) {
+				return err
+			}
+			return fmt.Errorf("重命名临时文件失败: %v", err)
+		}
+		syncDir(filepath.Dir(entry.Target))
+	}
+
+	for _, entry := range op.Entries {
+		if err := os.Remove(entry.Temp); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("删除临时文件失败: %v", err)
+		}
+	}
+
+	for _, entry := range op.Entries {
+		if entry.Source == "" {
+			continue
+		}
+		// 目标文件确实存在才删除源文件，避免丢失内容
+		if _, err := os.Stat(entry.Target); err != nil {
+			return fmt.Errorf("目标文件不存在: %s", entry.Target)
+		}
+		if err := os.Remove(entry.Source); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("删除源文件失败: %v", err)
+		}
+		syncDir(filepath.Dir(entry.Source))
+	}
+
+	return op.remove()
+}
+
+// linkNoReplace 让 newpath 指向 oldpath 的内容并保留 oldpath，newpath 已存在时返回 ErrTargetExists
+//
+// 创建硬链接和检查目标是否存在是同一个原子操作；newpath 已经是 oldpath 的链接时
+// （上次中断前已经创建）直接返回。文件系统不支持硬链接时退回到检查后重命名。
+func linkNoReplace(oldpath, newpath string) error {
+	err := os.Link(oldpath, newpath)
+	if err == nil {
+		return nil
+	}
+	if os.IsExist(err) {
+		if sameFile(oldpath, newpath) {
+			return nil
+		}
+		return fmt.Errorf("%w: %s", ErrTargetExists, newpath)
+	}
+
+	if _, statErr := os.Lstat(newpath); statErr == nil {
+		return fmt.Errorf("%w: %s", ErrTargetExists, newpath)
+	}
+	return os.Rename(oldpath, newpath)
+}
+
+// sameFile 两个路径是否指向同一个文件
+func sameFile(a, b string) bool {
+	aInfo, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bInfo, err := os.Stat(b)
+	return err == nil && os.SameFile(aInfo, bInfo)
+}
+
+// rollback 删除临时文件和已经链接到目标位置的文件，源文件保持不变
+func (op *Operation) rollback() error {
+	for _, entry := range op.Entries {
+		if entry.Temp == "" {
+			continue
+		}
+		if entry.Source != "" && sameFile(entry.Temp, entry.Target) {
+			if err := os.Remove(entry.Target); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("删除目标文件失败: %v", err)
+			}
+		}
+		if err := os.Remove(entry.Temp); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("删除临时文件失败: %v", err)
+		}
+	}
+	return op.remove()
+}
+
+// save 写入日志并 fsync
+func (op *Operation) save() error {
+	data, err := json.MarshalIndent(op, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeSynced(op.path, data, 0644); err != nil {
+		return fmt.Errorf("写入日志失败: %v", err)
+	}
+	syncDir(filepath.Dir(op.path))
+	return nil
+}
+
+// remove 删除日志
+func (op *Operation) remove() error {
+	if err := os.Remove(op.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("删除日志失败: %v", err)
+	}
+	return nil
+}
+
+// writeSynced 写入文件并 fsync，确保内容落盘
+func writeSynced(path string, data []byte, perm os.FileMode) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// syncDir fsync 目录以持久化其中的重命名和删除，部分平台不支持时忽略
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
@@ -0,0 +1,288 @@
+package journal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func mustWrite(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustContent(t *testing.T, path, want string) {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("读取 %s 失败: %v", path, err)
+	}
+	if string(content) != want {
+		t.Errorf("%s = %q, want %q", path, content, want)
+	}
+}
+
+func mustNotExist(t *testing.T, path string) {
+	t.Helper()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("%s 仍然存在: %v", path, err)
+	}
+}
+
+func TestMove(t *testing.T) {
+	t.Chdir(t.TempDir())
+	mustWrite(t, "a.md", "旧内容")
+
+	if err := Move("a.md", "b.md", []byte("新内容"), 0644); err != nil {
+		t.Fatalf("Move: %v", err)
+	}
+	mustContent(t, "b.md", "新内容")
+	mustNotExist(t, "a.md")
+
+	mustWrite(t, "a.md", "另一篇")
+	if err := Move("a.md", "b.md", []byte("另一篇"), 0644); !errors.Is(err, ErrTargetExists) {
+		t.Errorf("Move 到已存在的文件 = %v, want ErrTargetExists", err)
+	}
+	mustContent(t, "a.md", "另一篇")
+	mustContent(t, "b.md", "新内容")
+
+	if ops, _ := Pending(); len(ops) != 0 {
+		t.Errorf("遗留了 %d 个日志", len(ops))
+	}
+}
+
+// TestWriteFile 改写已有文件，不遗留临时文件和日志
+func TestWriteFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+	mustWrite(t, "README.md", "旧内容")
+
+	if err := WriteFile("README.md", []byte("新内容"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	mustContent(t, "README.md", "新内容")
+	if entries, _ := os.ReadDir("."); len(entries) != 2 {
+		t.Errorf("遗留了临时文件: %v", entries)
+	}
+	if ops, _ := Pending(); len(ops) != 0 {
+		t.Errorf("遗留了 %d 个日志", len(ops))
+	}
+}
+
+// TestLinkNoReplace 检查之后才出现的目标文件也不会被覆盖
+func TestLinkNoReplace(t *testing.T) {
+	t.Chdir(t.TempDir())
+	mustWrite(t, "temp", "新内容")
+	mustWrite(t, "target", "其他进程写入的内容")
+
+	if err := linkNoReplace("temp", "target"); !errors.Is(err, ErrTargetExists) {
+		t.Fatalf("linkNoReplace = %v, want ErrTargetExists", err)
+	}
+	mustContent(t, "target", "其他进程写入的内容")
+	mustContent(t, "temp", "新内容")
+}
+
+// TestMoveAll 任何一个目标文件已存在时所有文件都不移动
+func TestMoveAll(t *testing.T) {
+	t.Chdir(t.TempDir())
+	mustWrite(t, "a.md", "文章")
+	mustWrite(t, "a.png", "图片")
+	mustWrite(t, "b.png", "已存在的图片")
+
+	err := MoveAll([]FileMove{
+		{Source: "a.md", Target: "b.md", Content: []byte("文章"), Perm: 0644},
+		{Source: "a.png", Target: "b.png", Content: []byte("图片"), Perm: 0644},
+	})
+	if !errors.Is(err, ErrTargetExists) {
+		t.Fatalf("MoveAll = %v, want ErrTargetExists", err)
+	}
+	mustContent(t, "a.md", "文章")
+	mustContent(t, "a.png", "图片")
+	mustContent(t, "b.png", "已存在的图片")
+	mustNotExist(t, "b.md")
+
+	if err := MoveAll([]FileMove{
+		{Source: "a.md", Target: "c.md", Content: []byte("文章"), Perm: 0644},
+		{Source: "a.png", Target: "c.png", Content: []byte("图片"), Perm: 0644},
+	}); err != nil {
+		t.Fatalf("MoveAll: %v", err)
+	}
+	mustContent(t, "c.md", "文章")
+	mustContent(t, "c.png", "图片")
+	mustNotExist(t, "a.md")
+	mustNotExist(t, "a.png")
+}
+
+// saveOperation 模拟中途退出时留下的日志
+func saveOperation(t *testing.T, op *Operation) {
+	t.Helper()
+	if err := os.MkdirAll(journalDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	op.path = filepath.Join(journalDir(), op.ID+".json")
+	if err := op.save(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	// 已提交的移动：目标文件被其他文件占用时回滚，源文件保持不变
+	mustWrite(t, "taken.md", "源文件")
+	mustWrite(t, ".taken-temp", "新内容")
+	mustWrite(t, "taken-target.md", "其他文件")
+	saveOperation(t, &Operation{
+		ID: "taken", Stage: StageCommitted,
+		Entries: []Entry{{
+			Source: filepath.Join(dir, "taken.md"),
+			Target: filepath.Join(dir, "taken-target.md"),
+			Temp:   filepath.Join(dir, ".taken-temp"),
+		}},
+	})
+
+	// 已提交的移动：链接已经创建，只差删除临时文件和源文件
+	mustWrite(t, "linked.md", "源文件")
+	mustWrite(t, ".linked-temp", "新内容")
+	if err := os.Link(".linked-temp", "linked-target.md"); err != nil {
+		t.Skipf("不支持硬链接: %v", err)
+	}
+	saveOperation(t, &Operation{
+		ID: "linked", Stage: StageCommitted,
+		Entries: []Entry{{
+			Source: filepath.Join(dir, "linked.md"),
+			Target: filepath.Join(dir, "linked-target.md"),
+			Temp:   filepath.Join(dir, ".linked-temp"),
+		}},
+	})
+
+	// 未提交的移动：回滚
+	mustWrite(t, "prepared.md", "源文件")
+	mustWrite(t, ".prepared-temp", "写了一半")
+	saveOperation(t, &Operation{
+		ID: "prepared", Stage: StagePrepared,
+		Entries: []Entry{{
+			Source: filepath.Join(dir, "prepared.md"),
+			Target: filepath.Join(dir, "prepared-target.md"),
+			Temp:   filepath.Join(dir, ".prepared-temp"),
+		}},
+	})
+
+	// 已提交的多文件移动：第一个文件已经链接，第二个文件的目标被占用，整体回滚
+	mustWrite(t, "partial.md", "源文章")
+	mustWrite(t, "partial.png", "源图片")
+	mustWrite(t, ".partial-md-temp", "新文章")
+	mustWrite(t, ".partial-png-temp", "新图片")
+	mustWrite(t, "partial-target.png", "其他图片")
+	if err := os.Link(".partial-md-temp", "partial-target.md"); err != nil {
+		t.Fatal(err)
+	}
+	saveOperation(t, &Operation{
+		ID: "partial", Stage: StageCommitted,
+		Entries: []Entry{
+			{
+				Source: filepath.Join(dir, "partial.md"),
+				Target: filepath.Join(dir, "partial-target.md"),
+				Temp:   filepath.Join(dir, ".partial-md-temp"),
+			},
+			{
+				Source: filepath.Join(dir, "partial.png"),
+				Target: filepath.Join(dir, "partial-target.png"),
+				Temp:   filepath.Join(dir, ".partial-png-temp"),
+			},
+		},
+	})
+
+	// 已提交的改写：临时文件替换目标文件
+	mustWrite(t, "written.md", "旧内容")
+	mustWrite(t, ".written-temp", "新内容")
+	saveOperation(t, &Operation{
+		ID: "written", Stage: StageCommitted,
+		Entries: []Entry{{
+			Target: filepath.Join(dir, "written.md"),
+			Temp:   filepath.Join(dir, ".written-temp"),
+		}},
+	})
+
+	ops, err := Recover()
+	if err != nil {
+		t.Fatalf("Recover: %v", err)
+	}
+	if len(ops) != 5 {
+		t.Errorf("恢复了 %d 个操作, want 5", len(ops))
+	}
+
+	mustContent(t, "written.md", "新内容")
+	mustNotExist(t, ".written-temp")
+
+	mustContent(t, "taken.md", "源文件")
+	mustContent(t, "taken-target.md", "其他文件")
+	mustNotExist(t, ".taken-temp")
+
+	mustContent(t, "linked-target.md", "新内容")
+	mustNotExist(t, "linked.md")
+	mustNotExist(t, ".linked-temp")
+
+	mustContent(t, "partial.md", "源文章")
+	mustContent(t, "partial.png", "源图片")
+	mustContent(t, "partial-target.png", "其他图片")
+	mustNotExist(t, "partial-target.md")
+	mustNotExist(t, ".partial-md-temp")
+	mustNotExist(t, ".partial-png-temp")
+
+	mustContent(t, "prepared.md", "源文件")
+	mustNotExist(t, "prepared-target.md")
+	mustNotExist(t, ".prepared-temp")
+
+	if ops, _ := Pending(); len(ops) != 0 {
+		t.Errorf("遗留了 %d 个日志", len(ops))
+	}
+}
+
+// TestRecoverWithoutJournal 没有日志时不创建 .myblog 目录
+func TestRecoverWithoutJournal(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if ops, err := Recover(); err != nil || len(ops) != 0 {
+		t.Fatalf("Recover = %d, %v", len(ops), err)
+	}
+	mustNotExist(t, Dir)
+}
+
+// TestLock 持有锁时其他操作等待锁释放
+func TestLock(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	unlock, err := lock()
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+
+	acquired := make(chan error, 1)
+	go func() {
+		unlock, err := lock()
+		if err == nil {
+			unlock()
+		}
+		acquired <- err
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("锁被其他操作同时获取")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	unlock()
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatalf("lock: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("释放后仍然无法获取锁")
+	}
+}
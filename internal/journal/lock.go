@@ -0,0 +1,33 @@
+package journal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// lockPath 锁文件的路径，同一项目中同时运行的多个 myblog 进程依次执行操作和恢复
+func lockPath() string {
+	return filepath.Join(Dir, "lock")
+}
+
+// lock 获取项目的文件操作锁，其他进程持有锁时等待，返回释放锁的函数
+//
+// 锁由操作系统在进程退出时自动释放，程序崩溃后不会留下失效的锁。
+func lock() (func(), error) {
+	if err := os.MkdirAll(Dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建 %s 目录失败: %v", Dir, err)
+	}
+	file, err := os.OpenFile(lockPath(), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("打开锁文件失败: %v", err)
+	}
+	if err := lockFile(file); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("获取文件锁失败: %v", err)
+	}
+	return func() {
+		unlockFile(file)
+		file.Close()
+	}, nil
+}
@@ -0,0 +1,14 @@
+//go:build !unix && !windows
+
+package journal
+
+import "os"
+
+// 不支持文件锁的平台上不加锁
+func lockFile(file *os.File) error {
+	return nil
+}
+
+func unlockFile(file *os.File) error {
+	return nil
+}
@@ -0,0 +1,117 @@
+// Package slug 根据文章标题生成文件名和页面地址使用的 slug
+package slug
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/mozillazg/go-pinyin"
+)
+
+// Strategy slug 的生成策略
+type Strategy string
+
+const (
+	// Keep 保留原文（包括中文），只替换文件名中不能使用的字符
+	Keep Strategy = "keep"
+	// Pinyin 将中文转换为不带声调的拼音，单词之间用连字符连接
+	Pinyin Strategy = "pinyin"
+	// Hash 包含非 ASCII 字符的标题使用标题的哈希值
+	Hash Strategy = "hash"
+)
+
+// Strategies 所有可用的策略
+var Strategies = []string{string(Keep), string(Pinyin), string(Hash)}
+
+// ParseStrategy 解析策略名称，为空时使用 Keep
+func ParseStrategy(name string) (Strategy, error) {
+	switch Strategy(strings.ToLower(name)) {
+	case "", Keep:
+		return Keep, nil
+	case Pinyin:
+		return Pinyin, nil
+	case Hash:
+		return Hash, nil
+	}
+	return "", fmt.Errorf("不支持的slug策略: %s (可选: %s)", name, strings.Join(Strategies, ", "))
+}
+
+// Make 按策略将标题转换为 slug，结果为空时返回 "untitled"
+func Make(title string, strategy Strategy) string {
+	var s string
+	switch strategy {
+	case Pinyin:
+		s = makePinyin(title)
+	case Hash:
+		s = makeHash(title)
+	default:
+		s = makeKeep(title)
+	}
+
+	if s == "" {
+		return "untitled"
+	}
+	return s
+}
+
+// makeKeep 转为小写并将空格和特殊字符替换为下划线
+func makeKeep(title string) string {
+	s := strings.ToLower(title)
+
+	specialChars := []string{" ", "/", "\\", ":", "*", "?", "\"", "<", ">", "|", "。", "，", "！", "？", "；", "："}
+	for _, char := range specialChars {
+		s = strings.ReplaceAll(s, char, "_")
+	}
+
+	// 移除连续的下划线
+	for strings.Contains(s, "__") {
+		s = strings.ReplaceAll(s, "__", "_")
+	}
+
+	return strings.Trim(s, "_")
+}
+
+// makePinyin 汉字转换为拼音，字母和数字保持不变，其余字符作为分隔符
+func makePinyin(title string) string {
+	args := pinyin.NewArgs()
+
+	var words []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+
+	for _, r := range title {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flush()
+			if p := pinyin.SinglePinyin(r, args); len(p) > 0 && p[0] != "" {
+				words = append(words, p[0])
+			}
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			word.WriteRune(unicode.ToLower(r))
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return strings.Join(words, "-")
+}
+
+// makeHash 纯 ASCII 标题与 Keep 相同，否则使用标题 SHA-1 的前 8 位
+func makeHash(title string) string {
+	for _, r := range title {
+		if r >= unicode.MaxASCII {
+			sum := sha1.Sum([]byte(title))
+			return hex.EncodeToString(sum[:])[:8]
+		}
+	}
+	return makeKeep(title)
+}
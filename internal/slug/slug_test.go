@@ -0,0 +1,60 @@
+package slug
+
+import "testing"
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		title    string
+		strategy Strategy
+		want     string
+	}{
+		{"Go 语言并发编程", Keep, "go_语言并发编程"},
+		{"Hello: World?", Keep, "hello_world"},
+		{"你好，世界！", Keep, "你好_世界"},
+		{"  ", Keep, "untitled"},
+
+		{"Go 语言并发编程", Pinyin, "go-yu-yan-bing-fa-bian-cheng"},
+		{"Go语言2.0新特性", Pinyin, "go-yu-yan-2-0-xin-te-xing"},
+		{"Docker和K8s入门", Pinyin, "docker-he-k8s-ru-men"},
+		{"Hello World", Pinyin, "hello-world"},
+		{"你好，世界！", Pinyin, "ni-hao-shi-jie"},
+		{"！？", Pinyin, "untitled"},
+
+		{"Go 语言并发编程", Hash, "6244e233"},
+		{"你好", Hash, "440ee085"},
+		{"Hello World", Hash, "hello_world"},
+	}
+
+	for _, tt := range tests {
+		if got := Make(tt.title, tt.strategy); got != tt.want {
+			t.Errorf("Make(%q, %s) = %q, want %q", tt.title, tt.strategy, got, tt.want)
+		}
+	}
+}
+
+// TestHashStable 相同的标题总是得到相同的 hash，不同的标题不同
+func TestHashStable(t *testing.T) {
+	first := Make("Go 语言并发编程", Hash)
+	for i := 0; i < 3; i++ {
+		if got := Make("Go 语言并发编程", Hash); got != first {
+			t.Fatalf("第 %d 次 = %s, want %s", i+2, got, first)
+		}
+	}
+	if other := Make("Go 语言并发编程 2", Hash); other == first {
+		t.Errorf("不同的标题得到相同的 hash: %s", other)
+	}
+	if len(first) != 8 {
+		t.Errorf("hash 长度 = %d, want 8", len(first))
+	}
+}
+
+func TestParseStrategy(t *testing.T) {
+	for name, want := range map[string]Strategy{"": Keep, "keep": Keep, "Pinyin": Pinyin, "HASH": Hash} {
+		if got, err := ParseStrategy(name); err != nil || got != want {
+			t.Errorf("ParseStrategy(%q) = %s, %v, want %s", name, got, err, want)
+		}
+	}
+	if _, err := ParseStrategy("ascii"); err == nil {
+		t.Error("ParseStrategy(ascii) 应返回错误")
+	}
+}
@@ -3,19 +3,18 @@ package main
 import (
 	"MyBlog/cmd"
 	"MyBlog/internal/config"
+	"MyBlog/internal/fsys"
+	"MyBlog/internal/journal"
 	"fmt"
 	"os"
 
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
-func main() {
-	// 初始化配置
-	if err := config.InitConfig(); err != nil {
-		fmt.Fprintf(os.Stderr, "配置初始化失败: %v\n", err)
-		os.Exit(1)
-	}
+var dryRun bool
 
+func main() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
@@ -29,11 +28,57 @@ var rootCmd = &cobra.Command{
 它专为命令行使用而设计，借助GitHub对Markdown文档的完美支持。
 
 对我们来说，Markdown文档就是界面。`,
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		if fsys.DryRun() {
+			yellow := color.New(color.FgYellow).SprintFunc()
+			fmt.Printf("%s 预演模式，没有修改任何文件\n", yellow("[dry-run]"))
+		}
+	},
 }
 
 func init() {
+	// 配置在解析命令行参数之后初始化，以便 --dry-run 生效
+	cobra.OnInitialize(initialize)
+
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "只显示将要创建、移动、删除或改写的文件，不修改磁盘")
+
 	rootCmd.AddCommand(cmd.DraftCmd)
 	rootCmd.AddCommand(cmd.PubCmd)
 	rootCmd.AddCommand(cmd.NewCmd)
 	rootCmd.AddCommand(cmd.GenCmd)
+	rootCmd.AddCommand(cmd.ConfigCmd)
+	rootCmd.AddCommand(cmd.BuildCmd)
+	rootCmd.AddCommand(cmd.ServeCmd)
+	rootCmd.AddCommand(cmd.FeedCmd)
+	rootCmd.AddCommand(cmd.SitemapCmd)
+	rootCmd.AddCommand(cmd.ListCmd)
+	rootCmd.AddCommand(cmd.UnpubCmd)
+	rootCmd.AddCommand(cmd.ScheduleCmd)
+	rootCmd.AddCommand(cmd.CheckCmd)
+	rootCmd.AddCommand(cmd.AssetCmd)
+	rootCmd.AddCommand(cmd.MoveCmd)
+}
+
+func initialize() {
+	fsys.SetDryRun(dryRun)
+
+	// 初始化配置
+	if err := config.InitConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "配置初始化失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 恢复上次中断的文件操作，预演模式下只提示
+	if dryRun {
+		if ops, err := journal.Pending(); err == nil && len(ops) > 0 {
+			fmt.Fprintf(os.Stderr, "有 %d 个上次中断的文件操作，将在下次正常运行时恢复\n", len(ops))
+		}
+		return
+	}
+	if ops, err := journal.Recover(); err != nil {
+		fmt.Fprintf(os.Stderr, "恢复未完成的操作失败: %v\n", err)
+		os.Exit(1)
+	} else if len(ops) > 0 {
+		fmt.Fprintf(os.Stderr, "已恢复 %d 个上次中断的文件操作\n", len(ops))
+	}
 }